@@ -0,0 +1,148 @@
+package htpasswd
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+var _ datasource.DataSource = &FileDataSource{}
+var _ datasource.DataSourceWithValidateConfig = &FileDataSource{}
+
+type FileDataSource struct{}
+
+type FileDataSourceModel struct {
+	Path    types.String     `tfsdk:"path"`
+	Content types.String     `tfsdk:"content"`
+	Entries []FileEntryModel `tfsdk:"entries"`
+}
+
+type FileEntryModel struct {
+	Username  types.String `tfsdk:"username"`
+	Hash      types.String `tfsdk:"hash"`
+	Algorithm types.String `tfsdk:"algorithm"`
+	Salt      types.String `tfsdk:"salt"`
+	Cost      types.Int64  `tfsdk:"cost"`
+	Rounds    types.Int64  `tfsdk:"rounds"`
+}
+
+func NewFileDataSource() datasource.DataSource {
+	return &FileDataSource{}
+}
+
+func (d *FileDataSource) Metadata(_ context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_file"
+}
+
+func (d *FileDataSource) Schema(_ context.Context, _ datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Parses an existing htpasswd file",
+		Attributes: map[string]schema.Attribute{
+			"path": schema.StringAttribute{
+				Optional:    true,
+				Description: "Path of the htpasswd file to read. Exactly one of path or content must be set.",
+			},
+			"content": schema.StringAttribute{
+				Optional:    true,
+				Computed:    true,
+				Description: "Content of the htpasswd file. Set to the file content when path is used.",
+			},
+			"entries": schema.ListNestedAttribute{
+				Computed:    true,
+				Description: "The user entries of the file in file order",
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"username": schema.StringAttribute{
+							Computed:    true,
+							Description: "The username",
+						},
+						"hash": schema.StringAttribute{
+							Computed:    true,
+							Description: "The hash as found in the file",
+						},
+						"algorithm": schema.StringAttribute{
+							Computed:    true,
+							Description: "The detected hash algorithm: apr1, argon2id, bcrypt, pbkdf2_sha512, scrypt, sha1, sha256_crypt, sha512, yescrypt, md5_crypt, des_crypt or unknown",
+						},
+						"salt": schema.StringAttribute{
+							Computed:    true,
+							Description: "The salt as encoded in the hash, null when the algorithm has none or is unknown",
+						},
+						"cost": schema.Int64Attribute{
+							Computed:    true,
+							Description: "The bcrypt cost, null for other algorithms",
+						},
+						"rounds": schema.Int64Attribute{
+							Computed:    true,
+							Description: "The sha256_crypt or sha512 rounds or the pbkdf2_sha512 iterations, null for other algorithms",
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func (d *FileDataSource) ValidateConfig(ctx context.Context, req datasource.ValidateConfigRequest, resp *datasource.ValidateConfigResponse) {
+	var data FileDataSourceModel
+
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if data.Path.IsUnknown() || data.Content.IsUnknown() {
+		return
+	}
+	if data.Path.IsNull() == data.Content.IsNull() {
+		resp.Diagnostics.AddAttributeError(path.Root("path"), "Invalid Configuration", "Exactly one of path or content must be set")
+	}
+}
+
+func (d *FileDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var data FileDataSourceModel
+
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if !data.Path.IsNull() {
+		content, err := os.ReadFile(data.Path.ValueString())
+		if err != nil {
+			resp.Diagnostics.AddError("File Error", fmt.Sprintf("Failed to read %s: %s", data.Path.ValueString(), err))
+			return
+		}
+		data.Content = types.StringValue(string(content))
+	}
+
+	data.Entries = []FileEntryModel{}
+	for _, entry := range parseHtpasswd(data.Content.ValueString()) {
+		info := describeHash(entry.Hash)
+		model := FileEntryModel{
+			Username:  types.StringValue(entry.Username),
+			Hash:      types.StringValue(entry.Hash),
+			Algorithm: types.StringValue(info.Algorithm),
+			Salt:      types.StringNull(),
+			Cost:      types.Int64Null(),
+			Rounds:    types.Int64Null(),
+		}
+		if info.Salt != "" {
+			model.Salt = types.StringValue(info.Salt)
+		}
+		if info.Cost != 0 {
+			model.Cost = types.Int64Value(info.Cost)
+		}
+		if info.Rounds != 0 {
+			model.Rounds = types.Int64Value(info.Rounds)
+		}
+		data.Entries = append(data.Entries, model)
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
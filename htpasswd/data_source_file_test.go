@@ -0,0 +1,98 @@
+package htpasswd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+const testAccDataSourceFileContent = `# managed by hand
+alice:$2y$05$CCCCCCCCCCCCCCCCCCCCC.E5YPO9kmyuRGyh0XouQYb4YMJKvyOeW
+bob:{SHA}W6ph5Mm5Pz8GgiULbPgzG37mj9g=
+
+carol:$6$rounds=100000$12341234$gCCybFnOp4SRlwpV6fDDQv3uXM1s4JHhuaP3oSdN3Y5O0wJSfgLM3CJGgr/It3JlSv/QAiM11MkkzvUjXTHXm.
+dave:abJnggxhB/yWI
+`
+
+func TestAccDataSourceFile_Path(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), ".htpasswd")
+	if err := os.WriteFile(filename, []byte(testAccDataSourceFileContent), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(`
+data "htpasswd_file" "test" {
+	path = %q
+}
+`, filename),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("data.htpasswd_file.test", "content", testAccDataSourceFileContent),
+					resource.TestCheckResourceAttr("data.htpasswd_file.test", "entries.#", "4"),
+					resource.TestCheckResourceAttr("data.htpasswd_file.test", "entries.0.username", "alice"),
+					resource.TestCheckResourceAttr("data.htpasswd_file.test", "entries.0.algorithm", "bcrypt"),
+					resource.TestCheckResourceAttr("data.htpasswd_file.test", "entries.0.salt", "CCCCCCCCCCCCCCCCCCCCC."),
+					resource.TestCheckResourceAttr("data.htpasswd_file.test", "entries.0.cost", "5"),
+					resource.TestCheckNoResourceAttr("data.htpasswd_file.test", "entries.0.rounds"),
+					resource.TestCheckResourceAttr("data.htpasswd_file.test", "entries.1.username", "bob"),
+					resource.TestCheckResourceAttr("data.htpasswd_file.test", "entries.1.hash", "{SHA}W6ph5Mm5Pz8GgiULbPgzG37mj9g="),
+					resource.TestCheckResourceAttr("data.htpasswd_file.test", "entries.1.algorithm", "sha1"),
+					resource.TestCheckNoResourceAttr("data.htpasswd_file.test", "entries.1.salt"),
+					resource.TestCheckResourceAttr("data.htpasswd_file.test", "entries.2.algorithm", "sha512"),
+					resource.TestCheckResourceAttr("data.htpasswd_file.test", "entries.2.salt", "12341234"),
+					resource.TestCheckResourceAttr("data.htpasswd_file.test", "entries.2.rounds", "100000"),
+					resource.TestCheckResourceAttr("data.htpasswd_file.test", "entries.3.algorithm", "des_crypt"),
+					resource.TestCheckResourceAttr("data.htpasswd_file.test", "entries.3.salt", "ab"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccDataSourceFile_Content(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: `
+data "htpasswd_file" "test" {
+	content = "alice:$apr1$saltySal$a2VqVYQs.Pn9WnYqB9arf.\n"
+}
+
+output "legacy_users" {
+	value = join(",", [for e in data.htpasswd_file.test.entries : e.username if e.algorithm == "apr1"])
+}
+`,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("data.htpasswd_file.test", "entries.#", "1"),
+					resource.TestCheckResourceAttr("data.htpasswd_file.test", "entries.0.salt", "saltySal"),
+					resource.TestCheckOutput("legacy_users", "alice"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccDataSourceFile_PathAndContent(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: `
+data "htpasswd_file" "test" {
+	path    = "/etc/htpasswd"
+	content = ""
+}
+`,
+				ExpectError: regexp.MustCompile(`Exactly one of path or content must be set`),
+			},
+		},
+	})
+}
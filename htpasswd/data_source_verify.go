@@ -0,0 +1,232 @@
+package htpasswd
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+var _ datasource.DataSource = &VerifyDataSource{}
+var _ datasource.DataSourceWithConfigure = &VerifyDataSource{}
+
+type VerifyDataSource struct {
+	config *providerConfig
+}
+
+type VerifyDataSourceModel struct {
+	Password          types.String `tfsdk:"password"`
+	Hash              types.String `tfsdk:"hash"`
+	BcryptCost        types.Int64  `tfsdk:"bcrypt_cost"`
+	Sha256Rounds      types.Int64  `tfsdk:"sha256_rounds"`
+	Sha512Rounds      types.Int64  `tfsdk:"sha512_rounds"`
+	Pbkdf2Iterations  types.Int64  `tfsdk:"pbkdf2_iterations"`
+	Argon2Memory      types.Int64  `tfsdk:"argon2_memory"`
+	Argon2Time        types.Int64  `tfsdk:"argon2_time"`
+	Argon2Parallelism types.Int64  `tfsdk:"argon2_parallelism"`
+	ScryptN           types.Int64  `tfsdk:"scrypt_n"`
+	ScryptR           types.Int64  `tfsdk:"scrypt_r"`
+	ScryptP           types.Int64  `tfsdk:"scrypt_p"`
+	Valid             types.Bool   `tfsdk:"valid"`
+	Algorithm         types.String `tfsdk:"algorithm"`
+	NeedsRehash       types.Bool   `tfsdk:"needs_rehash"`
+	Salt              types.String `tfsdk:"salt"`
+	Cost              types.Int64  `tfsdk:"cost"`
+	Rounds            types.Int64  `tfsdk:"rounds"`
+	Memory            types.Int64  `tfsdk:"memory"`
+	Time              types.Int64  `tfsdk:"time"`
+	Parallelism       types.Int64  `tfsdk:"parallelism"`
+	N                 types.Int64  `tfsdk:"n"`
+	R                 types.Int64  `tfsdk:"r"`
+	P                 types.Int64  `tfsdk:"p"`
+}
+
+func NewVerifyDataSource() datasource.DataSource {
+	return &VerifyDataSource{}
+}
+
+func (d *VerifyDataSource) Metadata(_ context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_verify"
+}
+
+func (d *VerifyDataSource) Schema(_ context.Context, _ datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Verifies a password against an existing hash and checks the hash against the hashing policy",
+		Attributes: map[string]schema.Attribute{
+			"password": schema.StringAttribute{
+				Required:    true,
+				Sensitive:   true,
+				Description: "The password to verify",
+			},
+			"hash": schema.StringAttribute{
+				Required:    true,
+				Description: "The hash to verify the password against",
+			},
+			"bcrypt_cost": schema.Int64Attribute{
+				Optional:    true,
+				Description: "Minimum cost of bcrypt hashes (4-31). Defaults to the provider bcrypt_cost, or 10.",
+			},
+			"sha256_rounds": schema.Int64Attribute{
+				Optional:    true,
+				Description: "Minimum rounds of sha256_crypt hashes (1000-999999999). Defaults to the provider sha256_rounds, or 5000.",
+			},
+			"sha512_rounds": schema.Int64Attribute{
+				Optional:    true,
+				Description: "Minimum rounds of sha512 hashes (1000-999999999). Defaults to the provider sha512_rounds, or 5000.",
+			},
+			"pbkdf2_iterations": schema.Int64Attribute{
+				Optional:    true,
+				Description: "Minimum iterations of pbkdf2_sha512 hashes (1000-2147483647). Defaults to 210000.",
+			},
+			"argon2_memory": schema.Int64Attribute{
+				Optional:    true,
+				Description: "Minimum memory in KiB of argon2id hashes. Defaults to 65536.",
+			},
+			"argon2_time": schema.Int64Attribute{
+				Optional:    true,
+				Description: "Minimum iterations of argon2id hashes. Defaults to 3.",
+			},
+			"argon2_parallelism": schema.Int64Attribute{
+				Optional:    true,
+				Description: "Minimum degree of parallelism of argon2id hashes (1-255). Defaults to 4.",
+			},
+			"scrypt_n": schema.Int64Attribute{
+				Optional:    true,
+				Description: "Minimum CPU/memory cost parameter N of scrypt hashes. Must be a power of 2. Defaults to 32768.",
+			},
+			"scrypt_r": schema.Int64Attribute{
+				Optional:    true,
+				Description: "Minimum block size parameter r of scrypt hashes. Defaults to 8.",
+			},
+			"scrypt_p": schema.Int64Attribute{
+				Optional:    true,
+				Description: "Minimum parallelization parameter p of scrypt hashes. Defaults to 1.",
+			},
+			"valid": schema.BoolAttribute{
+				Computed:    true,
+				Description: "Whether hash is a hash of password. False for hashes in a format that can not be verified or too expensive to verify, such as a bcrypt cost above 14. Under fips_mode, apr1 and sha1 hashes are refused with an error; other hashes that are not FIPS approved are verified and have needs_rehash set.",
+			},
+			"algorithm": schema.StringAttribute{
+				Computed:    true,
+				Description: "The detected hash algorithm: apr1, argon2id, bcrypt, pbkdf2_sha512, scrypt, sha1, sha256_crypt, sha512, yescrypt, md5_crypt, des_crypt or unknown",
+			},
+			"needs_rehash": schema.BoolAttribute{
+				Computed:    true,
+				Description: "Whether the hash should be replaced: its algorithm is insecure, not allowed by the provider algorithms or fips_mode, or not produced by the provider, or its cost, rounds, iterations, or argon2id or scrypt parameters are below the minimum.",
+			},
+			"salt": schema.StringAttribute{
+				Computed:    true,
+				Description: "The salt as encoded in the hash, null when the algorithm has none or is unknown",
+			},
+			"cost": schema.Int64Attribute{
+				Computed:    true,
+				Description: "The bcrypt cost, null for other algorithms",
+			},
+			"rounds": schema.Int64Attribute{
+				Computed:    true,
+				Description: "The sha256_crypt or sha512 rounds or the pbkdf2_sha512 iterations, null for other algorithms",
+			},
+			"memory": schema.Int64Attribute{
+				Computed:    true,
+				Description: "The argon2id memory in KiB, null for other algorithms",
+			},
+			"time": schema.Int64Attribute{
+				Computed:    true,
+				Description: "The argon2id iterations, null for other algorithms",
+			},
+			"parallelism": schema.Int64Attribute{
+				Computed:    true,
+				Description: "The argon2id degree of parallelism, null for other algorithms",
+			},
+			"n": schema.Int64Attribute{
+				Computed:    true,
+				Description: "The scrypt CPU/memory cost parameter N, null for other algorithms",
+			},
+			"r": schema.Int64Attribute{
+				Computed:    true,
+				Description: "The scrypt block size parameter r, null for other algorithms",
+			},
+			"p": schema.Int64Attribute{
+				Computed:    true,
+				Description: "The scrypt parallelization parameter p, null for other algorithms",
+			},
+		},
+	}
+}
+
+func (d *VerifyDataSource) Configure(_ context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+	config, ok := req.ProviderData.(*providerConfig)
+	if !ok {
+		resp.Diagnostics.AddError("Unexpected Provider Data", fmt.Sprintf("Expected *providerConfig, got %T", req.ProviderData))
+		return
+	}
+	d.config = config
+}
+
+func (d *VerifyDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var data VerifyDataSourceModel
+
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	opts := d.config.hashOptions(HashOptions{
+		BcryptCost:        data.BcryptCost.ValueInt64(),
+		Sha256Rounds:      data.Sha256Rounds.ValueInt64(),
+		Sha512Rounds:      data.Sha512Rounds.ValueInt64(),
+		Pbkdf2Iterations:  data.Pbkdf2Iterations.ValueInt64(),
+		Argon2Memory:      data.Argon2Memory.ValueInt64(),
+		Argon2Time:        data.Argon2Time.ValueInt64(),
+		Argon2Parallelism: data.Argon2Parallelism.ValueInt64(),
+		ScryptN:           data.ScryptN.ValueInt64(),
+		ScryptR:           data.ScryptR.ValueInt64(),
+		ScryptP:           data.ScryptP.ValueInt64(),
+	})
+	resp.Diagnostics.Append(validateHashOptions(opts)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	hash := data.Hash.ValueString()
+	info := describeHash(hash)
+	if err := d.config.validateVerify(info.Algorithm); err != nil {
+		resp.Diagnostics.AddAttributeError(path.Root("hash"), "Invalid Algorithm", err.Error())
+		return
+	}
+
+	hasher, ok := detectHasher(hash)
+	data.Valid = types.BoolValue(ok && hasher.Verify(data.Password.ValueString(), hash, HashOptions{}))
+
+	data.Algorithm = types.StringValue(info.Algorithm)
+	data.NeedsRehash = types.BoolValue(info.needsRehash(opts))
+	data.Salt = types.StringNull()
+	if info.Salt != "" {
+		data.Salt = types.StringValue(info.Salt)
+	}
+	data.Cost = int64ValueOrNull(info.Cost)
+	data.Rounds = int64ValueOrNull(info.Rounds)
+	data.Memory = int64ValueOrNull(info.Argon2Memory)
+	data.Time = int64ValueOrNull(info.Argon2Time)
+	data.Parallelism = int64ValueOrNull(info.Argon2Parallelism)
+	data.N = int64ValueOrNull(info.ScryptN)
+	data.R = int64ValueOrNull(info.ScryptR)
+	data.P = int64ValueOrNull(info.ScryptP)
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+// int64ValueOrNull returns value as an Int64 value, or null when it is 0,
+// which describeHash uses for parameters the algorithm does not have.
+func int64ValueOrNull(value int64) types.Int64 {
+	if value == 0 {
+		return types.Int64Null()
+	}
+	return types.Int64Value(value)
+}
@@ -0,0 +1,187 @@
+package htpasswd
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+func TestAccDataSourceVerify_Basic(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: `
+data "htpasswd_verify" "bcrypt" {
+	password = "U*U"
+	hash     = "$2y$05$CCCCCCCCCCCCCCCCCCCCC.E5YPO9kmyuRGyh0XouQYb4YMJKvyOeW"
+}
+
+data "htpasswd_verify" "bcrypt_policy" {
+	password    = "U*U"
+	hash        = "$2y$05$CCCCCCCCCCCCCCCCCCCCC.E5YPO9kmyuRGyh0XouQYb4YMJKvyOeW"
+	bcrypt_cost = 5
+}
+
+data "htpasswd_verify" "sha512" {
+	password = "secret123"
+	hash     = "$6$rounds=100000$12341234$gCCybFnOp4SRlwpV6fDDQv3uXM1s4JHhuaP3oSdN3Y5O0wJSfgLM3CJGgr/It3JlSv/QAiM11MkkzvUjXTHXm."
+}
+
+data "htpasswd_verify" "sha1" {
+	password = "password"
+	hash     = "{SHA}W6ph5Mm5Pz8GgiULbPgzG37mj9g="
+}
+
+data "htpasswd_verify" "wrong" {
+	password = "wrong"
+	hash     = "$5$saltySal$4Ty5aUmBWGOEccaQocjg2RAQ6V3ISGcIXzqmeGQqsuC"
+}
+
+data "htpasswd_verify" "des_crypt" {
+	password = "password"
+	hash     = "abJnggxhB/yWI"
+}
+`,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("data.htpasswd_verify.bcrypt", "valid", "true"),
+					resource.TestCheckResourceAttr("data.htpasswd_verify.bcrypt", "algorithm", "bcrypt"),
+					resource.TestCheckResourceAttr("data.htpasswd_verify.bcrypt", "salt", "CCCCCCCCCCCCCCCCCCCCC."),
+					resource.TestCheckResourceAttr("data.htpasswd_verify.bcrypt", "cost", "5"),
+					resource.TestCheckResourceAttr("data.htpasswd_verify.bcrypt", "needs_rehash", "true"),
+					resource.TestCheckResourceAttr("data.htpasswd_verify.bcrypt_policy", "needs_rehash", "false"),
+					resource.TestCheckResourceAttr("data.htpasswd_verify.sha512", "valid", "true"),
+					resource.TestCheckResourceAttr("data.htpasswd_verify.sha512", "rounds", "100000"),
+					resource.TestCheckResourceAttr("data.htpasswd_verify.sha512", "needs_rehash", "false"),
+					resource.TestCheckNoResourceAttr("data.htpasswd_verify.sha512", "cost"),
+					resource.TestCheckNoResourceAttr("data.htpasswd_verify.sha512", "memory"),
+					resource.TestCheckNoResourceAttr("data.htpasswd_verify.sha512", "p"),
+					resource.TestCheckResourceAttr("data.htpasswd_verify.sha1", "valid", "true"),
+					resource.TestCheckResourceAttr("data.htpasswd_verify.sha1", "algorithm", "sha1"),
+					resource.TestCheckResourceAttr("data.htpasswd_verify.sha1", "needs_rehash", "true"),
+					resource.TestCheckNoResourceAttr("data.htpasswd_verify.sha1", "salt"),
+					resource.TestCheckResourceAttr("data.htpasswd_verify.wrong", "valid", "false"),
+					resource.TestCheckResourceAttr("data.htpasswd_verify.wrong", "algorithm", "sha256_crypt"),
+					resource.TestCheckResourceAttr("data.htpasswd_verify.wrong", "rounds", "5000"),
+					resource.TestCheckResourceAttr("data.htpasswd_verify.wrong", "needs_rehash", "false"),
+					resource.TestCheckResourceAttr("data.htpasswd_verify.des_crypt", "valid", "false"),
+					resource.TestCheckResourceAttr("data.htpasswd_verify.des_crypt", "algorithm", "des_crypt"),
+					resource.TestCheckResourceAttr("data.htpasswd_verify.des_crypt", "needs_rehash", "true"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccDataSourceVerify_Parameters(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: `
+data "htpasswd_verify" "argon2id" {
+	password = "password"
+	hash     = "$argon2id$v=19$m=65536,t=2,p=1$c29tZXNhbHQ$CTFhFdXPJO1aFaMaO6Mm5c8y7cJHAph8ArZWb2GRPPc"
+}
+
+data "htpasswd_verify" "argon2id_policy" {
+	password           = "password"
+	hash               = "$argon2id$v=19$m=65536,t=2,p=1$c29tZXNhbHQ$CTFhFdXPJO1aFaMaO6Mm5c8y7cJHAph8ArZWb2GRPPc"
+	argon2_time        = 2
+	argon2_parallelism = 1
+}
+
+data "htpasswd_verify" "scrypt" {
+	password = "password"
+	hash     = "$7$C6..../....saltySal$hvzBk0EYXkCKqLwZoYZvYNiIB8XbLM0XS9F0k46YGe8"
+}
+
+data "htpasswd_verify" "scrypt_policy" {
+	password = "password"
+	hash     = "$scrypt$ln=14,r=8,p=1$c2FsdHlTYWw$7f43sACRI+xY9sWXNFnuZOZSjTKeFyaM3hIJsIGQkqo"
+	scrypt_n = 16384
+}
+`,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("data.htpasswd_verify.argon2id", "valid", "true"),
+					resource.TestCheckResourceAttr("data.htpasswd_verify.argon2id", "needs_rehash", "true"),
+					resource.TestCheckResourceAttr("data.htpasswd_verify.argon2id", "memory", "65536"),
+					resource.TestCheckResourceAttr("data.htpasswd_verify.argon2id", "time", "2"),
+					resource.TestCheckResourceAttr("data.htpasswd_verify.argon2id", "parallelism", "1"),
+					resource.TestCheckNoResourceAttr("data.htpasswd_verify.argon2id", "n"),
+					resource.TestCheckResourceAttr("data.htpasswd_verify.argon2id_policy", "needs_rehash", "false"),
+					resource.TestCheckResourceAttr("data.htpasswd_verify.scrypt", "valid", "true"),
+					resource.TestCheckResourceAttr("data.htpasswd_verify.scrypt", "needs_rehash", "true"),
+					resource.TestCheckResourceAttr("data.htpasswd_verify.scrypt", "n", "16384"),
+					resource.TestCheckResourceAttr("data.htpasswd_verify.scrypt", "r", "8"),
+					resource.TestCheckResourceAttr("data.htpasswd_verify.scrypt", "p", "1"),
+					resource.TestCheckNoResourceAttr("data.htpasswd_verify.scrypt", "memory"),
+					resource.TestCheckResourceAttr("data.htpasswd_verify.scrypt_policy", "needs_rehash", "false"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccDataSourceVerify_ProviderPolicy(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: `
+provider "htpasswd" {
+	fips_mode     = true
+	sha512_rounds = 200000
+}
+
+data "htpasswd_verify" "sha256_crypt" {
+	password = "secret123"
+	hash     = "$5$saltySal$4Ty5aUmBWGOEccaQocjg2RAQ6V3ISGcIXzqmeGQqsuC"
+}
+
+data "htpasswd_verify" "sha512" {
+	password = "secret123"
+	hash     = "$6$rounds=100000$12341234$gCCybFnOp4SRlwpV6fDDQv3uXM1s4JHhuaP3oSdN3Y5O0wJSfgLM3CJGgr/It3JlSv/QAiM11MkkzvUjXTHXm."
+}
+
+data "htpasswd_verify" "bcrypt" {
+	password    = "U*U"
+	hash        = "$2y$05$CCCCCCCCCCCCCCCCCCCCC.E5YPO9kmyuRGyh0XouQYb4YMJKvyOeW"
+	bcrypt_cost = 5
+}
+`,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("data.htpasswd_verify.sha256_crypt", "needs_rehash", "false"),
+					resource.TestCheckResourceAttr("data.htpasswd_verify.sha512", "valid", "true"),
+					resource.TestCheckResourceAttr("data.htpasswd_verify.sha512", "needs_rehash", "true"),
+					resource.TestCheckResourceAttr("data.htpasswd_verify.bcrypt", "valid", "true"),
+					resource.TestCheckResourceAttr("data.htpasswd_verify.bcrypt", "needs_rehash", "true"),
+				),
+			},
+			{
+				Config: `
+provider "htpasswd" {
+	fips_mode = true
+}
+
+data "htpasswd_verify" "test" {
+	password = "password"
+	hash     = "$apr1$saltySal$a2VqVYQs.Pn9WnYqB9arf."
+}
+`,
+				ExpectError: regexp.MustCompile(`apr1 hashes are built on MD5 or SHA-1`),
+			},
+			{
+				Config: `
+data "htpasswd_verify" "test" {
+	password    = "U*U"
+	hash        = "$2y$05$CCCCCCCCCCCCCCCCCCCCC.E5YPO9kmyuRGyh0XouQYb4YMJKvyOeW"
+	bcrypt_cost = 3
+}
+`,
+				ExpectError: regexp.MustCompile(`bcrypt cost must be between 4 and 31`),
+			},
+		},
+	})
+}
@@ -3,27 +3,96 @@ package htpasswd
 import (
 	"context"
 	"fmt"
+	"strings"
 
+	"github.com/hashicorp/terraform-plugin-framework/attr"
 	"github.com/hashicorp/terraform-plugin-framework/ephemeral"
 	"github.com/hashicorp/terraform-plugin-framework/ephemeral/schema"
+	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/types"
-	"github.com/johnaoss/htpasswd/apr1"
-	"golang.org/x/crypto/bcrypt"
 )
 
 var _ ephemeral.EphemeralResource = &PasswordEphemeral{}
+var _ ephemeral.EphemeralResourceWithConfigure = &PasswordEphemeral{}
+var _ ephemeral.EphemeralResourceWithValidateConfig = &PasswordEphemeral{}
 
-type PasswordEphemeral struct{}
+type PasswordEphemeral struct {
+	config *providerConfig
+}
 
 type PasswordEphemeralModel struct {
-	Password   types.String `tfsdk:"password"`
-	Salt       types.String `tfsdk:"salt"`
-	LegacyHash types.Bool   `tfsdk:"legacy_hash"`
-	Apr1       types.String `tfsdk:"apr1"`
-	Bcrypt     types.String `tfsdk:"bcrypt"`
-	Sha1       types.String `tfsdk:"sha1"`
-	Sha256     types.String `tfsdk:"sha256"`
-	Sha512     types.String `tfsdk:"sha512"`
+	Password          types.String `tfsdk:"password"`
+	Salt              types.String `tfsdk:"salt"`
+	SaltContext       types.String `tfsdk:"salt_context"`
+	LegacyHash        types.Bool   `tfsdk:"legacy_hash"`
+	Sha256Rounds      types.Int64  `tfsdk:"sha256_rounds"`
+	Sha512Rounds      types.Int64  `tfsdk:"sha512_rounds"`
+	BcryptCost        types.Int64  `tfsdk:"bcrypt_cost"`
+	BcryptVariant     types.String `tfsdk:"bcrypt_variant"`
+	BcryptSalt        types.String `tfsdk:"bcrypt_salt"`
+	Argon2Memory      types.Int64  `tfsdk:"argon2_memory"`
+	Argon2Time        types.Int64  `tfsdk:"argon2_time"`
+	Argon2Parallelism types.Int64  `tfsdk:"argon2_parallelism"`
+	ScryptN           types.Int64  `tfsdk:"scrypt_n"`
+	ScryptR           types.Int64  `tfsdk:"scrypt_r"`
+	ScryptP           types.Int64  `tfsdk:"scrypt_p"`
+	ScryptFormat      types.String `tfsdk:"scrypt_format"`
+	Pbkdf2Iterations  types.Int64  `tfsdk:"pbkdf2_iterations"`
+	Username          types.String `tfsdk:"username"`
+	Realm             types.String `tfsdk:"realm"`
+	Algorithms        types.Set    `tfsdk:"algorithms"`
+	Argon2id          types.String `tfsdk:"argon2id"`
+	Apr1              types.String `tfsdk:"apr1"`
+	Bcrypt            types.String `tfsdk:"bcrypt"`
+	Htdigest          types.String `tfsdk:"htdigest"`
+	Pbkdf2Sha512      types.String `tfsdk:"pbkdf2_sha512"`
+	Scrypt            types.String `tfsdk:"scrypt"`
+	Sha1              types.String `tfsdk:"sha1"`
+	Sha256            types.String `tfsdk:"sha256"`
+	Sha256Crypt       types.String `tfsdk:"sha256_crypt"`
+	Sha512            types.String `tfsdk:"sha512"`
+	Yescrypt          types.String `tfsdk:"yescrypt"`
+}
+
+// hashOptions returns the hash settings configured on the ephemeral resource.
+func (m *PasswordEphemeralModel) hashOptions() HashOptions {
+	return HashOptions{
+		Salt:              m.Salt.ValueString(),
+		LegacyHash:        m.LegacyHash.ValueBool(),
+		Sha256Rounds:      m.Sha256Rounds.ValueInt64(),
+		Sha512Rounds:      m.Sha512Rounds.ValueInt64(),
+		BcryptCost:        m.BcryptCost.ValueInt64(),
+		BcryptVariant:     m.BcryptVariant.ValueString(),
+		BcryptSalt:        m.BcryptSalt.ValueString(),
+		Argon2Memory:      m.Argon2Memory.ValueInt64(),
+		Argon2Time:        m.Argon2Time.ValueInt64(),
+		Argon2Parallelism: m.Argon2Parallelism.ValueInt64(),
+		ScryptN:           m.ScryptN.ValueInt64(),
+		ScryptR:           m.ScryptR.ValueInt64(),
+		ScryptP:           m.ScryptP.ValueInt64(),
+		ScryptFormat:      m.ScryptFormat.ValueString(),
+		Pbkdf2Iterations:  m.Pbkdf2Iterations.ValueInt64(),
+		Username:          m.Username.ValueString(),
+		Realm:             m.Realm.ValueString(),
+		Algorithms:        algorithmsValue(m.Algorithms),
+	}
+}
+
+// hashValues maps the Hasher names to the hash attributes of the model.
+func (m *PasswordEphemeralModel) hashValues() map[string]*types.String {
+	return map[string]*types.String{
+		"apr1":          &m.Apr1,
+		"argon2id":      &m.Argon2id,
+		"bcrypt":        &m.Bcrypt,
+		"htdigest":      &m.Htdigest,
+		"pbkdf2_sha512": &m.Pbkdf2Sha512,
+		"scrypt":        &m.Scrypt,
+		"sha1":          &m.Sha1,
+		"sha256":        &m.Sha256,
+		"sha256_crypt":  &m.Sha256Crypt,
+		"sha512":        &m.Sha512,
+		"yescrypt":      &m.Yescrypt,
+	}
 }
 
 func NewPasswordEphemeral() ephemeral.EphemeralResource {
@@ -45,12 +114,85 @@ func (r *PasswordEphemeral) Schema(_ context.Context, _ ephemeral.SchemaRequest,
 			},
 			"salt": schema.StringAttribute{
 				Optional:    true,
-				Description: "Salt for apr1 and sha512 hashes. Must be exactly 8 characters from the crypt base64 alphabet (unless legacy_hash is true).",
+				Description: "Salt for apr1, sha256_crypt, sha512, argon2id, pbkdf2_sha512, scrypt and yescrypt hashes. Must be exactly 8 characters from the crypt base64 alphabet (unless legacy_hash is true). The pbkdf2_sha512 hash uses the first 16 bytes of the SHA-256 digest of the salt, as SP 800-132 requires salts of at least 128 bits.",
+			},
+			"salt_context": schema.StringAttribute{
+				Optional:    true,
+				Description: "Context from which the salt and, unless bcrypt_salt is set, the bcrypt salt are derived with the provider salt_derivation_key, e.g. the username. The same context derives the same salts on every run. Can not be combined with salt.",
 			},
 			"legacy_hash": schema.BoolAttribute{
 				Optional:    true,
 				Description: "When true, uses pre-1.6.0 salt handling which allows flexible salt lengths (1-16 characters). Use this to maintain compatibility with existing password hashes.",
 			},
+			"sha256_rounds": schema.Int64Attribute{
+				Optional:    true,
+				Description: "Number of rounds for the sha256_crypt hash (1000-999999999). When set, the hash includes a rounds=N$ segment.",
+			},
+			"sha512_rounds": schema.Int64Attribute{
+				Optional:    true,
+				Description: "Number of rounds for the sha512 hash (1000-999999999). When set, the hash includes a rounds=N$ segment.",
+			},
+			"bcrypt_cost": schema.Int64Attribute{
+				Optional:    true,
+				Description: "Cost factor for the bcrypt hash (4-31). Defaults to 10.",
+			},
+			"bcrypt_variant": schema.StringAttribute{
+				Optional:    true,
+				Description: "Prefix variant for the bcrypt hash: 2a, 2b or 2y. Defaults to 2a.",
+			},
+			"bcrypt_salt": schema.StringAttribute{
+				Optional:    true,
+				Description: "22 character bcrypt salt using the characters [./A-Za-z0-9]. When set, or derived from salt_context, the bcrypt hash is the same on every run. Otherwise a random salt is used.",
+			},
+			"argon2_memory": schema.Int64Attribute{
+				Optional:    true,
+				Description: "Memory in KiB used for the argon2id hash. Defaults to 65536.",
+			},
+			"argon2_time": schema.Int64Attribute{
+				Optional:    true,
+				Description: "Number of iterations used for the argon2id hash. Defaults to 3.",
+			},
+			"argon2_parallelism": schema.Int64Attribute{
+				Optional:    true,
+				Description: "Degree of parallelism used for the argon2id hash (1-255). Defaults to 4.",
+			},
+			"scrypt_n": schema.Int64Attribute{
+				Optional:    true,
+				Description: "CPU/memory cost parameter N for the scrypt hash. Must be a power of 2. Defaults to 32768.",
+			},
+			"scrypt_r": schema.Int64Attribute{
+				Optional:    true,
+				Description: "Block size parameter r for the scrypt hash. Defaults to 8.",
+			},
+			"scrypt_p": schema.Int64Attribute{
+				Optional:    true,
+				Description: "Parallelization parameter p for the scrypt hash. Defaults to 1.",
+			},
+			"scrypt_format": schema.StringAttribute{
+				Optional:    true,
+				Description: "Output format of the scrypt hash: phc ($scrypt$ln=...) or crypt ($7$). Defaults to phc.",
+			},
+			"pbkdf2_iterations": schema.Int64Attribute{
+				Optional:    true,
+				Description: "Number of iterations for the pbkdf2_sha512 hash (1000-2147483647). Defaults to 210000.",
+			},
+			"username": schema.StringAttribute{
+				Optional:    true,
+				Description: "Username for the htdigest hash. Requires realm.",
+			},
+			"realm": schema.StringAttribute{
+				Optional:    true,
+				Description: "Realm for the htdigest hash. Requires username.",
+			},
+			"algorithms": schema.SetAttribute{
+				Optional:    true,
+				ElementType: types.StringType,
+				Description: "Hashes to compute: " + strings.Join(hasherNames(), ", ") + ". The attributes of other hashes are null. Defaults to the provider algorithms, or all.",
+			},
+			"argon2id": schema.StringAttribute{
+				Computed:    true,
+				Description: "Argon2id hash of the password in PHC string format",
+			},
 			"apr1": schema.StringAttribute{
 				Computed:    true,
 				Description: "APR1-MD5 hash of the password",
@@ -59,6 +201,18 @@ func (r *PasswordEphemeral) Schema(_ context.Context, _ ephemeral.SchemaRequest,
 				Computed:    true,
 				Description: "Bcrypt hash of the password",
 			},
+			"htdigest": schema.StringAttribute{
+				Computed:    true,
+				Description: "htdigest entry user:realm:MD5(user:realm:password), null unless username and realm are set",
+			},
+			"pbkdf2_sha512": schema.StringAttribute{
+				Computed:    true,
+				Description: "PBKDF2-HMAC-SHA512 hash of the password in passlib $pbkdf2-sha512$ format",
+			},
+			"scrypt": schema.StringAttribute{
+				Computed:    true,
+				Description: "Scrypt hash of the password",
+			},
 			"sha1": schema.StringAttribute{
 				Computed:    true,
 				Description: "SHA1 crypt hash of the password (insecure)",
@@ -67,15 +221,35 @@ func (r *PasswordEphemeral) Schema(_ context.Context, _ ephemeral.SchemaRequest,
 				Computed:    true,
 				Description: "SHA-256 hash of the password (hex encoded)",
 			},
+			"sha256_crypt": schema.StringAttribute{
+				Computed:    true,
+				Description: "SHA-256 crypt hash of the password",
+			},
 			"sha512": schema.StringAttribute{
 				Computed:    true,
 				Description: "SHA-512 crypt hash of the password",
 			},
+			"yescrypt": schema.StringAttribute{
+				Computed:    true,
+				Description: "Yescrypt hash of the password",
+			},
 		},
 	}
 }
 
-func (r *PasswordEphemeral) Open(ctx context.Context, req ephemeral.OpenRequest, resp *ephemeral.OpenResponse) {
+func (r *PasswordEphemeral) Configure(_ context.Context, req ephemeral.ConfigureRequest, resp *ephemeral.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+	config, ok := req.ProviderData.(*providerConfig)
+	if !ok {
+		resp.Diagnostics.AddError("Unexpected Provider Data", fmt.Sprintf("Expected *providerConfig, got %T", req.ProviderData))
+		return
+	}
+	r.config = config
+}
+
+func (r *PasswordEphemeral) ValidateConfig(ctx context.Context, req ephemeral.ValidateConfigRequest, resp *ephemeral.ValidateConfigResponse) {
 	var data PasswordEphemeralModel
 
 	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
@@ -83,35 +257,47 @@ func (r *PasswordEphemeral) Open(ctx context.Context, req ephemeral.OpenRequest,
 		return
 	}
 
-	password := data.Password.ValueString()
-	salt := data.Salt.ValueString()
-	legacyHash := data.LegacyHash.ValueBool()
+	if !data.Salt.IsNull() && !data.SaltContext.IsNull() {
+		resp.Diagnostics.AddAttributeError(path.Root("salt_context"), "Invalid Configuration", "salt_context can not be combined with salt")
+	}
+	resp.Diagnostics.Append(validateConfigHashOptions(data.hashOptions(), map[string]attr.Value{
+		"legacy_hash":        data.LegacyHash,
+		"username":           data.Username,
+		"realm":              data.Realm,
+		"argon2_parallelism": data.Argon2Parallelism,
+	})...)
+}
+
+func (r *PasswordEphemeral) Open(ctx context.Context, req ephemeral.OpenRequest, resp *ephemeral.OpenResponse) {
+	var data PasswordEphemeralModel
 
-	// Validate salt based on legacy_hash setting
-	if err := validateSalt(salt, legacyHash); err != nil {
-		resp.Diagnostics.AddError("Invalid Salt", err.Error())
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
 		return
 	}
 
-	bcryptHash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
-	if err != nil {
-		resp.Diagnostics.AddError("Bcrypt Error", fmt.Sprintf("Failed to generate bcrypt hash: %s", err))
+	opts := r.config.hashOptions(data.hashOptions())
+	if !data.SaltContext.IsNull() {
+		salt, err := r.config.deriveSalt(data.SaltContext.ValueString())
+		if err != nil {
+			resp.Diagnostics.AddAttributeError(path.Root("salt_context"), "Salt Error", err.Error())
+			return
+		}
+		opts.Salt = salt
+		if opts.BcryptSalt == "" {
+			opts.BcryptSalt = deriveBcryptSalt(r.config.SaltDerivationKey, data.SaltContext.ValueString())
+		}
+	}
+	resp.Diagnostics.Append(validateHashOptions(opts)...)
+	resp.Diagnostics.Append(r.config.validate(opts)...)
+	if resp.Diagnostics.HasError() {
 		return
 	}
 
-	apr1Hash, err := apr1.Hash(password, salt)
-	if err != nil {
-		resp.Diagnostics.AddError("APR1 Error", fmt.Sprintf("Failed to generate APR1 hash: %s", err))
+	resp.Diagnostics.Append(generateHashes(data.Password.ValueString(), opts, data.hashValues())...)
+	if resp.Diagnostics.HasError() {
 		return
 	}
 
-	sha1hash := sha1Crypt(password)
-	sha512hash := sha512Crypt(password, salt)
-
-	data.Bcrypt = types.StringValue(string(bcryptHash))
-	data.Apr1 = types.StringValue(apr1Hash)
-	data.Sha1 = types.StringValue(sha1hash)
-	data.Sha512 = types.StringValue(sha512hash)
-
 	resp.Diagnostics.Append(resp.Result.Set(ctx, &data)...)
 }
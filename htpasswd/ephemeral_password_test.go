@@ -2,10 +2,12 @@ package htpasswd
 
 import (
 	"fmt"
+	"regexp"
 	"testing"
 
 	"github.com/hashicorp/go-version"
 	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/terraform"
 	"github.com/hashicorp/terraform-plugin-testing/tfversion"
 )
 
@@ -17,8 +19,7 @@ func TestAccEphemeralPassword_Basic(t *testing.T) {
 		},
 		Steps: []resource.TestStep{
 			{
-				Config:             testAccEphemeralPasswordBasicConfig("test1", "secret123", "saltySal"),
-				ExpectNonEmptyPlan: true,
+				Config: testAccEphemeralPasswordBasicConfig("test1", "secret123", "saltySal"),
 			},
 		},
 	})
@@ -32,18 +33,135 @@ func TestAccEphemeralPassword_WithLocalVariable(t *testing.T) {
 		},
 		Steps: []resource.TestStep{
 			{
-				Config:             testAccEphemeralPasswordWithLocalConfig("test2", "1234567890abcdefghijklmnopqrstuvwxyz", "12341234"),
-				ExpectNonEmptyPlan: true,
+				Config: testAccEphemeralPasswordWithLocalConfig("test2", "1234567890abcdefghijklmnopqrstuvwxyz", "12341234"),
 			},
 		},
 	})
 }
 
+func TestAccEphemeralPassword_SaltContextWithoutKey(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		TerraformVersionChecks: []tfversion.TerraformVersionCheck{
+			tfversion.SkipBelow(version.Must(version.NewVersion("1.10.0"))),
+		},
+		Steps: []resource.TestStep{
+			{
+				Config: `
+ephemeral "htpasswd_password" "test" {
+  password     = "secret123"
+  salt_context = "alice"
+}
+`,
+				ExpectError: regexp.MustCompile(`salt_context requires the provider salt_derivation_key`),
+			},
+		},
+	})
+}
+
+func TestAccEphemeralPassword_InvalidSettings(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		TerraformVersionChecks: []tfversion.TerraformVersionCheck{
+			tfversion.SkipBelow(version.Must(version.NewVersion("1.10.0"))),
+		},
+		Steps: []resource.TestStep{
+			{
+				Config: `
+ephemeral "htpasswd_password" "test" {
+  password = "secret123"
+  scrypt_n = 1000
+}
+`,
+				PlanOnly:    true,
+				ExpectError: regexp.MustCompile(`Invalid Scrypt Settings`),
+			},
+			{
+				Config: `
+ephemeral "htpasswd_password" "test" {
+  password     = "secret123"
+  salt         = "saltySal"
+  salt_context = "alice"
+}
+`,
+				PlanOnly:    true,
+				ExpectError: regexp.MustCompile(`salt_context can not be combined with salt`),
+			},
+		},
+	})
+}
+
+func TestAccEphemeralPassword_DeterministicBcrypt(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		TerraformVersionChecks: []tfversion.TerraformVersionCheck{
+			// The ephemeral bcrypt hash is passed to a write-only argument.
+			tfversion.SkipBelow(version.Must(version.NewVersion("1.11.0"))),
+		},
+		Steps: []resource.TestStep{
+			{
+				Config:      testAccEphemeralPasswordBcryptConfig(`bcrypt_salt = "CCCC"`),
+				ExpectError: regexp.MustCompile(`bcrypt salt must be exactly 22 characters long`),
+			},
+			{
+				Config: testAccEphemeralPasswordBcryptConfig(`salt_context = "alice"`),
+				Check:  testAccCheckEphemeralBcrypt("VtuFgIInBH9c2ZrOxu01je"),
+			},
+			{
+				Config: testAccEphemeralPasswordBcryptConfig(`bcrypt_salt = "CCCCCCCCCCCCCCCCCCCCC."`),
+				Check:  testAccCheckEphemeralBcrypt("CCCCCCCCCCCCCCCCCCCCC."),
+			},
+		},
+	})
+}
+
+// testAccEphemeralPasswordBcryptConfig hashes the ephemeral bcrypt hash of
+// secret123 again with sha512 in state, which makes it observable. A new
+// password_wo_version per configuration forces the hash to be recomputed.
+func testAccEphemeralPasswordBcryptConfig(bcryptSalt string) string {
+	return fmt.Sprintf(`
+provider "htpasswd" {
+  salt_derivation_key = "correct horse battery staple"
+}
+
+ephemeral "htpasswd_password" "test" {
+  password    = "secret123"
+  bcrypt_cost = 4
+  algorithms  = ["bcrypt"]
+  %s
+}
+
+resource "htpasswd_password" "test" {
+  password_wo         = ephemeral.htpasswd_password.test.bcrypt
+  password_wo_version = %d
+  salt                = "saltySal"
+  algorithms          = ["sha512"]
+}
+`, bcryptSalt, len(bcryptSalt))
+}
+
+// testAccCheckEphemeralBcrypt checks that the ephemeral bcrypt hash hashed in
+// state was generated with the given bcrypt salt.
+func testAccCheckEphemeralBcrypt(bcryptSalt string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		bcrypt, err := bcryptGenerate("secret123", 4, "", bcryptSalt)
+		if err != nil {
+			return err
+		}
+		want, err := sha512Hasher{}.Generate(bcrypt, HashOptions{Salt: "saltySal"})
+		if err != nil {
+			return err
+		}
+		return resource.TestCheckResourceAttr("htpasswd_password.test", "sha512", want)(s)
+	}
+}
+
 func testAccEphemeralPasswordBasicConfig(name, password, salt string) string {
 	return fmt.Sprintf(`
 ephemeral "htpasswd_password" "%s" {
-  password = "%s"
-  salt     = "%s"
+  password    = "%s"
+  salt        = "%s"
+  bcrypt_salt = "CCCCCCCCCCCCCCCCCCCCC."
 }
 `, name, password, salt)
 }
@@ -56,11 +174,15 @@ ephemeral "htpasswd_password" "%s" {
 }
 
 locals {
-  apr1_hash   = ephemeral.htpasswd_password.%s.apr1
-  bcrypt_hash = ephemeral.htpasswd_password.%s.bcrypt
-  sha1_hash   = ephemeral.htpasswd_password.%s.sha1
-  sha256_hash = ephemeral.htpasswd_password.%s.sha256
-  sha512_hash = ephemeral.htpasswd_password.%s.sha512
+  apr1_hash         = ephemeral.htpasswd_password.%s.apr1
+  argon2id_hash     = ephemeral.htpasswd_password.%s.argon2id
+  bcrypt_hash       = ephemeral.htpasswd_password.%s.bcrypt
+  scrypt_hash       = ephemeral.htpasswd_password.%s.scrypt
+  sha1_hash         = ephemeral.htpasswd_password.%s.sha1
+  sha256_hash       = ephemeral.htpasswd_password.%s.sha256
+  sha256_crypt_hash = ephemeral.htpasswd_password.%s.sha256_crypt
+  sha512_hash       = ephemeral.htpasswd_password.%s.sha512
+  yescrypt_hash     = ephemeral.htpasswd_password.%s.yescrypt
 }
-`, name, password, salt, name, name, name, name, name)
+`, name, password, salt, name, name, name, name, name, name, name, name, name)
 }
@@ -0,0 +1,324 @@
+package htpasswd
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-framework/function"
+)
+
+var _ function.Function = &HashFunction{}
+
+// HashFunction is a provider function returning a single hash of a password.
+// Provider functions must return the same result during plan and apply, so
+// every function takes its salt as an argument instead of generating one.
+type HashFunction struct {
+	hasher      Hasher
+	name        string
+	summary     string
+	description string
+	parameters  []function.Parameter
+	// arguments reads the password and hash options from the function
+	// arguments.
+	arguments func(ctx context.Context, args function.ArgumentsData) (string, HashOptions, *function.FuncError)
+}
+
+func NewApr1Function() function.Function {
+	return &HashFunction{
+		hasher:      apr1Hasher{},
+		name:        "apr1",
+		summary:     "Generate an apr1 hash",
+		description: "Returns the Apache apr1 (MD5) hash of password using the given 8 character salt.",
+		parameters:  []function.Parameter{passwordParameter(), saltParameter()},
+		arguments: func(ctx context.Context, args function.ArgumentsData) (string, HashOptions, *function.FuncError) {
+			var password, salt string
+			if funcErr := args.Get(ctx, &password, &salt); funcErr != nil {
+				return "", HashOptions{}, funcErr
+			}
+			return password, HashOptions{Salt: salt}, requireSalt(salt)
+		},
+	}
+}
+
+func NewArgon2idFunction() function.Function {
+	return &HashFunction{
+		hasher:      argon2idHasher{},
+		name:        "argon2id",
+		summary:     "Generate an argon2id hash",
+		description: "Returns the argon2id hash of password in PHC string format using the given 8 character salt. A memory, time or parallelism of 0 selects the default of 65536 KiB, 3 and 4 respectively.",
+		parameters: []function.Parameter{
+			passwordParameter(),
+			saltParameter(),
+			function.Int64Parameter{Name: "memory", Description: "Memory in KiB, or 0 for the default."},
+			function.Int64Parameter{Name: "time", Description: "Number of iterations, or 0 for the default."},
+			function.Int64Parameter{Name: "parallelism", Description: "Degree of parallelism, or 0 for the default."},
+		},
+		arguments: func(ctx context.Context, args function.ArgumentsData) (string, HashOptions, *function.FuncError) {
+			var password, salt string
+			var opts HashOptions
+			if funcErr := args.Get(ctx, &password, &salt, &opts.Argon2Memory, &opts.Argon2Time, &opts.Argon2Parallelism); funcErr != nil {
+				return "", HashOptions{}, funcErr
+			}
+			opts.Salt = salt
+			return password, opts, requireSalt(salt)
+		},
+	}
+}
+
+func NewBcryptFunction() function.Function {
+	return &HashFunction{
+		hasher:      bcryptHasher{},
+		name:        "bcrypt",
+		summary:     "Generate a bcrypt hash",
+		description: "Returns the bcrypt hash of password with the given cost, or the default cost of 10 when cost is 0, using the given 22 character bcrypt salt.",
+		parameters: []function.Parameter{
+			passwordParameter(),
+			function.Int64Parameter{Name: "cost", Description: "Bcrypt cost between 4 and 31, or 0 for the default."},
+			function.StringParameter{
+				Name:        "salt",
+				Description: "Salt of exactly 22 characters using the characters [./A-Za-z0-9].",
+			},
+		},
+		arguments: func(ctx context.Context, args function.ArgumentsData) (string, HashOptions, *function.FuncError) {
+			var password string
+			var opts HashOptions
+			if funcErr := args.Get(ctx, &password, &opts.BcryptCost, &opts.BcryptSalt); funcErr != nil {
+				return "", HashOptions{}, funcErr
+			}
+			if opts.BcryptSalt == "" {
+				return "", HashOptions{}, function.NewArgumentFuncError(2, "salt must not be empty")
+			}
+			return password, opts, nil
+		},
+	}
+}
+
+func NewHtdigestFunction() function.Function {
+	return &HashFunction{
+		hasher:      htdigestHasher{},
+		name:        "htdigest",
+		summary:     "Generate an htdigest entry",
+		description: "Returns the htdigest file entry username:realm:digest of password, where digest is the hex encoded MD5 digest of username:realm:password. This algorithm is insecure by today's standards.",
+		parameters: []function.Parameter{
+			passwordParameter(),
+			function.StringParameter{Name: "username", Description: "Username of the entry."},
+			function.StringParameter{Name: "realm", Description: "Realm of the entry, as in the AuthName directive."},
+		},
+		arguments: func(ctx context.Context, args function.ArgumentsData) (string, HashOptions, *function.FuncError) {
+			var password string
+			var opts HashOptions
+			if funcErr := args.Get(ctx, &password, &opts.Username, &opts.Realm); funcErr != nil {
+				return "", HashOptions{}, funcErr
+			}
+			if opts.Username == "" {
+				return "", HashOptions{}, function.NewArgumentFuncError(1, "username must not be empty")
+			}
+			if opts.Realm == "" {
+				return "", HashOptions{}, function.NewArgumentFuncError(2, "realm must not be empty")
+			}
+			return password, opts, nil
+		},
+	}
+}
+
+func NewPbkdf2Sha512Function() function.Function {
+	return &HashFunction{
+		hasher:      pbkdf2Sha512Hasher{},
+		name:        "pbkdf2_sha512",
+		summary:     "Generate a PBKDF2-SHA512 hash",
+		description: "Returns the passlib compatible PBKDF2-SHA512 ($pbkdf2-sha512$) hash of password with the given iterations, or the default of 210000 when iterations is 0. The 8 character salt is stretched to the first 16 bytes of its SHA-256 digest, as SP 800-132 requires salts of at least 128 bits.",
+		parameters: []function.Parameter{
+			passwordParameter(),
+			saltParameter(),
+			function.Int64Parameter{Name: "iterations", Description: "Number of iterations of at least 1000, or 0 for the default."},
+		},
+		arguments: func(ctx context.Context, args function.ArgumentsData) (string, HashOptions, *function.FuncError) {
+			var password, salt string
+			var opts HashOptions
+			if funcErr := args.Get(ctx, &password, &salt, &opts.Pbkdf2Iterations); funcErr != nil {
+				return "", HashOptions{}, funcErr
+			}
+			opts.Salt = salt
+			return password, opts, requireSalt(salt)
+		},
+	}
+}
+
+func NewScryptFunction() function.Function {
+	return &HashFunction{
+		hasher:      scryptHasher{},
+		name:        "scrypt",
+		summary:     "Generate a scrypt hash",
+		description: "Returns the scrypt hash of password using the given 8 character salt, in PHC string format or, with format crypt, in the libxcrypt $7$ format. An n, r or p of 0 selects the default of 32768, 8 and 1 respectively.",
+		parameters: []function.Parameter{
+			passwordParameter(),
+			saltParameter(),
+			function.Int64Parameter{Name: "n", Description: "CPU/memory cost, a power of two, or 0 for the default."},
+			function.Int64Parameter{Name: "r", Description: "Block size, or 0 for the default."},
+			function.Int64Parameter{Name: "p", Description: "Parallelism, or 0 for the default."},
+			function.StringParameter{Name: "format", Description: "Output format, phc or crypt, or an empty string for phc."},
+		},
+		arguments: func(ctx context.Context, args function.ArgumentsData) (string, HashOptions, *function.FuncError) {
+			var password, salt string
+			var opts HashOptions
+			if funcErr := args.Get(ctx, &password, &salt, &opts.ScryptN, &opts.ScryptR, &opts.ScryptP, &opts.ScryptFormat); funcErr != nil {
+				return "", HashOptions{}, funcErr
+			}
+			opts.Salt = salt
+			return password, opts, requireSalt(salt)
+		},
+	}
+}
+
+func NewSha1Function() function.Function {
+	return &HashFunction{
+		hasher:      sha1Hasher{},
+		name:        "sha1",
+		summary:     "Generate a SHA-1 hash",
+		description: "Returns the unsalted {SHA} hash of password. This algorithm is insecure by today's standards.",
+		parameters:  []function.Parameter{passwordParameter()},
+		arguments: func(ctx context.Context, args function.ArgumentsData) (string, HashOptions, *function.FuncError) {
+			var password string
+			if funcErr := args.Get(ctx, &password); funcErr != nil {
+				return "", HashOptions{}, funcErr
+			}
+			return password, HashOptions{}, nil
+		},
+	}
+}
+
+func NewSha256Function() function.Function {
+	return &HashFunction{
+		hasher:      sha256Hasher{},
+		name:        "sha256",
+		summary:     "Generate a salted SHA-256 digest",
+		description: "Returns the hex encoded SHA-256 digest of password and the given 8 character salt. This is not a crypt format, use sha256_crypt for htpasswd files.",
+		parameters:  []function.Parameter{passwordParameter(), saltParameter()},
+		arguments: func(ctx context.Context, args function.ArgumentsData) (string, HashOptions, *function.FuncError) {
+			var password, salt string
+			if funcErr := args.Get(ctx, &password, &salt); funcErr != nil {
+				return "", HashOptions{}, funcErr
+			}
+			return password, HashOptions{Salt: salt}, requireSalt(salt)
+		},
+	}
+}
+
+func NewSha256CryptFunction() function.Function {
+	return &HashFunction{
+		hasher:      sha256CryptHasher{},
+		name:        "sha256_crypt",
+		summary:     "Generate a SHA-256 crypt hash",
+		description: "Returns the SHA-256 crypt ($5$) hash of password using the given 8 character salt. A rounds value of 0 selects the default of 5000 rounds without a rounds= segment.",
+		parameters:  []function.Parameter{passwordParameter(), saltParameter(), roundsParameter()},
+		arguments: func(ctx context.Context, args function.ArgumentsData) (string, HashOptions, *function.FuncError) {
+			var password, salt string
+			var opts HashOptions
+			if funcErr := args.Get(ctx, &password, &salt, &opts.Sha256Rounds); funcErr != nil {
+				return "", HashOptions{}, funcErr
+			}
+			opts.Salt = salt
+			return password, opts, requireSalt(salt)
+		},
+	}
+}
+
+func NewSha512CryptFunction() function.Function {
+	return &HashFunction{
+		hasher:      sha512Hasher{},
+		name:        "sha512_crypt",
+		summary:     "Generate a SHA-512 crypt hash",
+		description: "Returns the SHA-512 crypt ($6$) hash of password using the given 8 character salt. A rounds value of 0 selects the default of 5000 rounds without a rounds= segment.",
+		parameters:  []function.Parameter{passwordParameter(), saltParameter(), roundsParameter()},
+		arguments: func(ctx context.Context, args function.ArgumentsData) (string, HashOptions, *function.FuncError) {
+			var password, salt string
+			var opts HashOptions
+			if funcErr := args.Get(ctx, &password, &salt, &opts.Sha512Rounds); funcErr != nil {
+				return "", HashOptions{}, funcErr
+			}
+			opts.Salt = salt
+			return password, opts, requireSalt(salt)
+		},
+	}
+}
+
+func NewYescryptFunction() function.Function {
+	return &HashFunction{
+		hasher:      yescryptHasher{},
+		name:        "yescrypt",
+		summary:     "Generate a yescrypt hash",
+		description: "Returns the yescrypt ($y$) hash of password using the given 8 character salt and the libxcrypt default cost.",
+		parameters:  []function.Parameter{passwordParameter(), saltParameter()},
+		arguments: func(ctx context.Context, args function.ArgumentsData) (string, HashOptions, *function.FuncError) {
+			var password, salt string
+			if funcErr := args.Get(ctx, &password, &salt); funcErr != nil {
+				return "", HashOptions{}, funcErr
+			}
+			return password, HashOptions{Salt: salt}, requireSalt(salt)
+		},
+	}
+}
+
+func (f *HashFunction) Metadata(_ context.Context, _ function.MetadataRequest, resp *function.MetadataResponse) {
+	resp.Name = f.name
+}
+
+func (f *HashFunction) Definition(_ context.Context, _ function.DefinitionRequest, resp *function.DefinitionResponse) {
+	resp.Definition = function.Definition{
+		Summary:     f.summary,
+		Description: f.description,
+		Parameters:  f.parameters,
+		Return:      function.StringReturn{},
+	}
+}
+
+func (f *HashFunction) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+	password, opts, funcErr := f.arguments(ctx, req.Arguments)
+	if funcErr != nil {
+		resp.Error = funcErr
+		return
+	}
+
+	resp.Error = function.FuncErrorFromDiags(ctx, validateHashOptions(opts))
+	if resp.Error != nil {
+		return
+	}
+
+	hash, err := f.hasher.Generate(password, opts)
+	if err != nil {
+		resp.Error = function.NewFuncError(fmt.Sprintf("Failed to generate %s hash: %s", f.hasher.Name(), err))
+		return
+	}
+
+	resp.Error = resp.Result.Set(ctx, hash)
+}
+
+func passwordParameter() function.Parameter {
+	return function.StringParameter{
+		Name:        "password",
+		Description: "Password to hash.",
+	}
+}
+
+func saltParameter() function.Parameter {
+	return function.StringParameter{
+		Name:        "salt",
+		Description: "Salt of exactly 8 characters using the characters [./A-Za-z0-9].",
+	}
+}
+
+func roundsParameter() function.Parameter {
+	return function.Int64Parameter{
+		Name:        "rounds",
+		Description: "Number of rounds between 1000 and 999999999, or 0 for the default.",
+	}
+}
+
+// requireSalt returns an error for the salt argument when it is empty. The
+// resources fall back to a random salt, which functions cannot do.
+func requireSalt(salt string) *function.FuncError {
+	if salt == "" {
+		return function.NewArgumentFuncError(1, "salt must not be empty")
+	}
+	return nil
+}
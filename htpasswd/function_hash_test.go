@@ -0,0 +1,213 @@
+package htpasswd
+
+import (
+	"fmt"
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/go-version"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/terraform"
+	"github.com/hashicorp/terraform-plugin-testing/tfversion"
+)
+
+func TestAccFunctionHash_KnownAnswers(t *testing.T) {
+	// Expected values are taken from OpenSSL, libxcrypt and the OpenWall bcrypt
+	// test vectors:
+	//   openssl passwd -apr1 -salt saltySal password
+	//   openssl passwd -5 -salt 'rounds=1000$saltySal' secret123
+	//   openssl passwd -6 -salt 'rounds=100000$12341234' secret123
+	//   crypt.crypt("password", "$y$j9T$saltySal")
+	//   crypt.crypt("password", "$7$C6..../....saltySal")
+	//   pbkdf2_sha512.using(salt=hashlib.sha256(b"saltySal").digest()[:16], rounds=1000).hash("password")
+	//   printf 'alice:private:password' | md5sum
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		TerraformVersionChecks: []tfversion.TerraformVersionCheck{
+			tfversion.SkipBelow(version.Must(version.NewVersion("1.8.0"))),
+		},
+		Steps: []resource.TestStep{
+			{
+				Config: `
+output "apr1" {
+	value = provider::htpasswd::apr1("password", "saltySal")
+}
+
+output "bcrypt" {
+	value = provider::htpasswd::bcrypt("U*U", 5, "CCCCCCCCCCCCCCCCCCCCC.")
+}
+
+output "htdigest" {
+	value = provider::htpasswd::htdigest("password", "alice", "private")
+}
+
+output "pbkdf2_sha512" {
+	value = provider::htpasswd::pbkdf2_sha512("password", "saltySal", 1000)
+}
+
+output "scrypt" {
+	value = provider::htpasswd::scrypt("password", "saltySal", 16384, 0, 0, "crypt")
+}
+
+output "sha1" {
+	value = provider::htpasswd::sha1("password")
+}
+
+output "sha256_crypt" {
+	value = provider::htpasswd::sha256_crypt("secret123", "saltySal", 1000)
+}
+
+output "sha512_crypt" {
+	value = provider::htpasswd::sha512_crypt("secret123", "12341234", 100000)
+}
+
+output "yescrypt" {
+	value = provider::htpasswd::yescrypt("password", "saltySal")
+}
+`,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckOutput("apr1", "$apr1$saltySal$a2VqVYQs.Pn9WnYqB9arf."),
+					resource.TestCheckOutput("bcrypt", "$2a$05$CCCCCCCCCCCCCCCCCCCCC.E5YPO9kmyuRGyh0XouQYb4YMJKvyOeW"),
+					resource.TestCheckOutput("htdigest", "alice:private:735389ca4b461bae2629b745d5eb3ebf"),
+					resource.TestCheckOutput("pbkdf2_sha512", "$pbkdf2-sha512$1000$drecY.N0qcSE5j5DapSZ/g$0QjoaS4Ainm/HCrEBm/OXC8jsOEBfo3.XKG8sJacbBpUWu1jMQXv7r6OaXSecYXV7W/mGPIwVJ8rH6y8t7i/6g"),
+					resource.TestCheckOutput("scrypt", "$7$C6..../....saltySal$hvzBk0EYXkCKqLwZoYZvYNiIB8XbLM0XS9F0k46YGe8"),
+					resource.TestCheckOutput("sha1", "{SHA}W6ph5Mm5Pz8GgiULbPgzG37mj9g="),
+					resource.TestCheckOutput("sha256_crypt", "$5$rounds=1000$saltySal$EVSFEd9pwEGvE7v0ceuYb5xLIcMCKvju5xmNm1ivjQ5"),
+					resource.TestCheckOutput("sha512_crypt", "$6$rounds=100000$12341234$gCCybFnOp4SRlwpV6fDDQv3uXM1s4JHhuaP3oSdN3Y5O0wJSfgLM3CJGgr/It3JlSv/QAiM11MkkzvUjXTHXm."),
+					resource.TestCheckOutput("yescrypt", "$y$j9T$saltySal$IhSdlMOWhMvju7xEQ.Xx3c.t372QErgDynv0Zw7pkOB"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccFunctionHash_Verifiable(t *testing.T) {
+	config := `
+locals {
+	password = "secret123"
+}
+
+output "argon2id" {
+	value = provider::htpasswd::argon2id(local.password, "saltySal", 1024, 1, 1)
+}
+
+output "bcrypt" {
+	value = provider::htpasswd::bcrypt(local.password, 4, "CCCCCCCCCCCCCCCCCCCCC.")
+}
+
+output "scrypt" {
+	value = provider::htpasswd::scrypt(local.password, "saltySal", 1024, 0, 0, "")
+}
+`
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		TerraformVersionChecks: []tfversion.TerraformVersionCheck{
+			tfversion.SkipBelow(version.Must(version.NewVersion("1.8.0"))),
+		},
+		Steps: []resource.TestStep{
+			{
+				Config: config,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckOutputVerifies("argon2id", "secret123", verifyArgon2id),
+					testAccCheckOutputVerifies("bcrypt", "secret123", verifyBcrypt),
+					testAccCheckOutputVerifies("scrypt", "secret123", verifyScrypt),
+				),
+			},
+			{
+				// The results must be stable for Terraform to accept them.
+				Config:   config,
+				PlanOnly: true,
+			},
+		},
+	})
+}
+
+func TestAccFunctionHash_InvalidArguments(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		TerraformVersionChecks: []tfversion.TerraformVersionCheck{
+			tfversion.SkipBelow(version.Must(version.NewVersion("1.8.0"))),
+		},
+		Steps: []resource.TestStep{
+			{
+				Config: `
+output "test" {
+	value = provider::htpasswd::apr1("password", "")
+}
+`,
+				ExpectError: regexp.MustCompile(`salt must not be empty`),
+			},
+			{
+				Config: `
+output "test" {
+	value = provider::htpasswd::sha512_crypt("password", "short", 0)
+}
+`,
+				ExpectError: regexp.MustCompile(`salt must be exactly 8 characters`),
+			},
+			{
+				Config: `
+output "test" {
+	value = provider::htpasswd::bcrypt("password", 3, "CCCCCCCCCCCCCCCCCCCCC.")
+}
+`,
+				ExpectError: regexp.MustCompile(`bcrypt cost must be between 4 and 31`),
+			},
+			{
+				Config: `
+output "test" {
+	value = provider::htpasswd::bcrypt("password", 4, "")
+}
+`,
+				ExpectError: regexp.MustCompile(`salt must not be empty`),
+			},
+			{
+				Config: `
+output "test" {
+	value = provider::htpasswd::bcrypt("password", 4)
+}
+`,
+				ExpectError: regexp.MustCompile(`Not enough function arguments`),
+			},
+			{
+				Config: `
+output "test" {
+	value = provider::htpasswd::htdigest("password", "alice", "")
+}
+`,
+				ExpectError: regexp.MustCompile(`realm must not be empty`),
+			},
+			{
+				Config: `
+output "test" {
+	value = provider::htpasswd::pbkdf2_sha512("password", "saltySal", 999)
+}
+`,
+				ExpectError: regexp.MustCompile(`pbkdf2 iterations must be between 1000`),
+			},
+			{
+				Config: `
+output "test" {
+	value = provider::htpasswd::scrypt("password", "saltySal", 0, 0, 0, "mcf")
+}
+`,
+				ExpectError: regexp.MustCompile(`scrypt format must be one of phc, crypt`),
+			},
+		},
+	})
+}
+
+func testAccCheckOutputVerifies(name, password string, verify func(password, hash string) bool) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		output, ok := s.RootModule().Outputs[name]
+		if !ok {
+			return fmt.Errorf("output %q not found", name)
+		}
+		hash, ok := output.Value.(string)
+		if !ok || !verify(password, hash) {
+			return fmt.Errorf("output %q = %v does not verify against the password", name, output.Value)
+		}
+		return nil
+	}
+}
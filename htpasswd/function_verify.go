@@ -0,0 +1,54 @@
+package htpasswd
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/function"
+)
+
+var _ function.Function = &VerifyFunction{}
+
+// VerifyFunction is a provider function checking a password against a hash in
+// any format the provider can produce.
+type VerifyFunction struct{}
+
+func NewVerifyFunction() function.Function {
+	return &VerifyFunction{}
+}
+
+func (f *VerifyFunction) Metadata(_ context.Context, _ function.MetadataRequest, resp *function.MetadataResponse) {
+	resp.Name = "verify"
+}
+
+func (f *VerifyFunction) Definition(_ context.Context, _ function.DefinitionRequest, resp *function.DefinitionResponse) {
+	resp.Definition = function.Definition{
+		Summary:     "Verify a password against a hash",
+		Description: "Returns true when hash is a hash of password. Supports the " + strings.Join(verifiableHasherNames(), ", ") + " formats. Returns an error for hashes in any other format.",
+		Parameters: []function.Parameter{
+			passwordParameter(),
+			function.StringParameter{
+				Name:        "hash",
+				Description: "Hash to verify the password against.",
+			},
+		},
+		Return: function.BoolReturn{},
+	}
+}
+
+func (f *VerifyFunction) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+	var password, hash string
+	resp.Error = req.Arguments.Get(ctx, &password, &hash)
+	if resp.Error != nil {
+		return
+	}
+
+	hasher, ok := detectHasher(hash)
+	if !ok {
+		resp.Error = function.NewArgumentFuncError(1, fmt.Sprintf("unsupported hash format, expected a hash starting with one of %s", hashPrefixList()))
+		return
+	}
+
+	resp.Error = resp.Result.Set(ctx, hasher.Verify(password, hash, HashOptions{}))
+}
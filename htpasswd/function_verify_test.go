@@ -0,0 +1,73 @@
+package htpasswd
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/go-version"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/tfversion"
+)
+
+func TestAccFunctionVerify_Basic(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		TerraformVersionChecks: []tfversion.TerraformVersionCheck{
+			tfversion.SkipBelow(version.Must(version.NewVersion("1.8.0"))),
+		},
+		Steps: []resource.TestStep{
+			{
+				Config: `
+locals {
+	hashes = {
+		apr1     = "$apr1$saltySal$a2VqVYQs.Pn9WnYqB9arf."
+		argon2id = "$argon2id$v=19$m=65536,t=2,p=1$c29tZXNhbHQ$CTFhFdXPJO1aFaMaO6Mm5c8y7cJHAph8ArZWb2GRPPc"
+		bcrypt   = provider::htpasswd::bcrypt("password", 4, "CCCCCCCCCCCCCCCCCCCCC.")
+		scrypt   = provider::htpasswd::scrypt("password", "saltySal", 1024, 0, 0, "")
+		sha1     = "{SHA}W6ph5Mm5Pz8GgiULbPgzG37mj9g="
+		sha256   = provider::htpasswd::sha256_crypt("password", "saltySal", 1000)
+		sha512   = provider::htpasswd::sha512_crypt("password", "saltySal", 0)
+		yescrypt = "$y$j9T$saltySal$IhSdlMOWhMvju7xEQ.Xx3c.t372QErgDynv0Zw7pkOB"
+	}
+}
+
+output "valid" {
+	value = alltrue([for hash in values(local.hashes) : provider::htpasswd::verify("password", hash)])
+}
+
+output "invalid" {
+	value = anytrue([for hash in values(local.hashes) : provider::htpasswd::verify("wrong", hash)])
+}
+
+output "bcrypt_2y" {
+	value = provider::htpasswd::verify("U*U", "$2y$05$CCCCCCCCCCCCCCCCCCCCC.E5YPO9kmyuRGyh0XouQYb4YMJKvyOeW")
+}
+`,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckOutput("valid", "true"),
+					resource.TestCheckOutput("invalid", "false"),
+					resource.TestCheckOutput("bcrypt_2y", "true"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccFunctionVerify_UnsupportedFormat(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		TerraformVersionChecks: []tfversion.TerraformVersionCheck{
+			tfversion.SkipBelow(version.Must(version.NewVersion("1.8.0"))),
+		},
+		Steps: []resource.TestStep{
+			{
+				Config: `
+output "test" {
+	value = provider::htpasswd::verify("password", "password")
+}
+`,
+				ExpectError: regexp.MustCompile(`unsupported hash format`),
+			},
+		},
+	})
+}
@@ -0,0 +1,618 @@
+package htpasswd
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/johnaoss/htpasswd/apr1"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// HashOptions holds the user supplied settings for all hash algorithms. Zero
+// values select the algorithm defaults. Algorithms limits the hashes generated
+// by generateHashes and refreshHashes to the named hashers, all hashes are
+// generated when it is empty.
+type HashOptions struct {
+	Salt              string
+	LegacyHash        bool
+	Sha256Rounds      int64
+	Sha512Rounds      int64
+	BcryptCost        int64
+	BcryptVariant     string
+	BcryptSalt        string
+	Argon2Memory      int64
+	Argon2Time        int64
+	Argon2Parallelism int64
+	ScryptN           int64
+	ScryptR           int64
+	ScryptP           int64
+	ScryptFormat      string
+	Username          string
+	Realm             string
+	Pbkdf2Iterations  int64
+	Algorithms        []string
+}
+
+// generates reports whether the hasher with the given name is selected by
+// opts.Algorithms.
+func (opts HashOptions) generates(name string) bool {
+	return len(opts.Algorithms) == 0 || slices.Contains(opts.Algorithms, name)
+}
+
+// changed returns the names of the hashes generated differently with opts than
+// with prior. Hashes are verified against the password only, so a hash with
+// outdated settings would otherwise be kept.
+func (opts HashOptions) changed(prior HashOptions) []string {
+	var names []string
+	if opts.Salt != prior.Salt {
+		names = append(names, "apr1", "argon2id", "pbkdf2_sha512", "scrypt", "sha256", "sha256_crypt", "sha512", "yescrypt")
+	}
+	if opts.BcryptCost != prior.BcryptCost || opts.BcryptVariant != prior.BcryptVariant || opts.BcryptSalt != prior.BcryptSalt {
+		names = append(names, "bcrypt")
+	}
+	if opts.Argon2Memory != prior.Argon2Memory || opts.Argon2Time != prior.Argon2Time || opts.Argon2Parallelism != prior.Argon2Parallelism {
+		names = append(names, "argon2id")
+	}
+	if opts.ScryptN != prior.ScryptN || opts.ScryptR != prior.ScryptR || opts.ScryptP != prior.ScryptP || opts.ScryptFormat != prior.ScryptFormat {
+		names = append(names, "scrypt")
+	}
+	if opts.Pbkdf2Iterations != prior.Pbkdf2Iterations {
+		names = append(names, "pbkdf2_sha512")
+	}
+	if opts.Sha256Rounds != prior.Sha256Rounds {
+		names = append(names, "sha256_crypt")
+	}
+	if opts.Sha512Rounds != prior.Sha512Rounds {
+		names = append(names, "sha512")
+	}
+	if opts.Username != prior.Username || opts.Realm != prior.Realm {
+		names = append(names, "htdigest")
+	}
+	return names
+}
+
+// Hasher generates and verifies password hashes for a single algorithm.
+type Hasher interface {
+	// Name returns the attribute name the hash is exposed as.
+	Name() string
+	// Generate returns a new hash of password. An empty hash means the hash is
+	// not available with the given options.
+	Generate(password string, opts HashOptions) (string, error)
+	// Verify reports whether hash is a valid hash of password.
+	Verify(password, hash string, opts HashOptions) bool
+}
+
+// hashers is the registry of all supported hash algorithms. Every resource
+// producing hashes iterates this list, so a new algorithm only needs to be
+// added here and to the schemas.
+var hashers = []Hasher{
+	apr1Hasher{},
+	argon2idHasher{},
+	bcryptHasher{},
+	htdigestHasher{},
+	pbkdf2Sha512Hasher{},
+	scryptHasher{},
+	sha1Hasher{},
+	sha256Hasher{},
+	sha256CryptHasher{},
+	sha512Hasher{},
+	yescryptHasher{},
+}
+
+// insecureAlgorithms lists the hashers based on MD5, SHA-1 or a single
+// SHA-256 round, which are refused by deny_insecure_algorithms.
+var insecureAlgorithms = []string{"apr1", "htdigest", "sha1", "sha256"}
+
+// fipsAlgorithms lists the hashers built on FIPS 140 approved primitives only,
+// which are the only ones allowed by fips_mode.
+var fipsAlgorithms = []string{"pbkdf2_sha512", "sha256_crypt", "sha512"}
+
+// md5SHA1Algorithms lists the hashers built on MD5 or SHA-1, whose hashes are
+// not verified under fips_mode either.
+var md5SHA1Algorithms = []string{"apr1", "htdigest", "sha1"}
+
+// validateAlgorithms validates a list of hasher names. A nil list selects all
+// hashers, an empty list is invalid. Insecure algorithms are refused when
+// denyInsecure is true, and algorithms that are not FIPS approved when fips is
+// true.
+func validateAlgorithms(algorithms []string, denyInsecure, fips bool) error {
+	if algorithms != nil && len(algorithms) == 0 {
+		return fmt.Errorf("algorithms must contain at least one algorithm")
+	}
+	for _, name := range algorithms {
+		if _, ok := hasherByName(name); !ok {
+			return fmt.Errorf("algorithms must only contain %s, got %q", strings.Join(hasherNames(), ", "), name)
+		}
+		if denyInsecure && slices.Contains(insecureAlgorithms, name) {
+			return fmt.Errorf("%s is insecure and denied by deny_insecure_algorithms", name)
+		}
+		if fips && !slices.Contains(fipsAlgorithms, name) {
+			return fmt.Errorf("%s is not FIPS approved and refused by fips_mode, use one of %s", name, strings.Join(fipsAlgorithms, ", "))
+		}
+	}
+	return nil
+}
+
+// algorithmsValue returns the hasher names of an algorithms set attribute,
+// or nil when the set is null or unknown.
+func algorithmsValue(set types.Set) []string {
+	if set.IsNull() || set.IsUnknown() {
+		return nil
+	}
+	algorithms := []string{}
+	for _, element := range set.Elements() {
+		if name, ok := element.(types.String); ok {
+			algorithms = append(algorithms, name.ValueString())
+		}
+	}
+	return algorithms
+}
+
+// hasherNames returns the names of all hashers.
+func hasherNames() []string {
+	names := make([]string, 0, len(hashers))
+	for _, h := range hashers {
+		names = append(names, h.Name())
+	}
+	return names
+}
+
+// hashPrefix maps the prefix of a hash format to the hasher that verifies it.
+type hashPrefix struct {
+	prefix string
+	hasher Hasher
+}
+
+// hashPrefixes lists the prefix of every hash format the provider produces.
+// The legacy sha256 digest has no prefix and can not be detected.
+var hashPrefixes = []hashPrefix{
+	{"$apr1$", apr1Hasher{}},
+	{"$argon2id$", argon2idHasher{}},
+	{"$2a$", bcryptHasher{}},
+	{"$2b$", bcryptHasher{}},
+	{"$2y$", bcryptHasher{}},
+	{"$pbkdf2-sha512$", pbkdf2Sha512Hasher{}},
+	{"$scrypt$", scryptHasher{}},
+	{"$7$", scryptHasher{}},
+	{"{SHA}", sha1Hasher{}},
+	{"$5$", sha256CryptHasher{}},
+	{"$6$", sha512Hasher{}},
+	{"$y$", yescryptHasher{}},
+}
+
+// detectHasher returns the hasher for the format of hash, based on its prefix.
+func detectHasher(hash string) (Hasher, bool) {
+	for _, p := range hashPrefixes {
+		if strings.HasPrefix(hash, p.prefix) {
+			return p.hasher, true
+		}
+	}
+	return nil, false
+}
+
+// hashPrefixList returns the supported hash prefixes for use in error
+// messages.
+func hashPrefixList() string {
+	prefixes := make([]string, 0, len(hashPrefixes))
+	for _, p := range hashPrefixes {
+		prefixes = append(prefixes, p.prefix)
+	}
+	return strings.Join(prefixes, ", ")
+}
+
+// verifiableHasherNames returns the names of the hashers whose hashes can be
+// detected and verified.
+func verifiableHasherNames() []string {
+	var names []string
+	for _, name := range hasherNames() {
+		if slices.ContainsFunc(hashPrefixes, func(p hashPrefix) bool { return p.hasher.Name() == name }) {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// Algorithm names of hash formats that are recognised but not produced.
+const (
+	algorithmMD5Crypt = "md5_crypt"
+	algorithmDESCrypt = "des_crypt"
+	algorithmUnknown  = "unknown"
+)
+
+// hashInfo describes the format and parameters of a hash.
+type hashInfo struct {
+	// Algorithm is the name of the hasher that produced the hash, one of the
+	// recognised legacy formats or unknown.
+	Algorithm string
+	// Salt is the salt as encoded in the hash, empty when the format has none.
+	Salt string
+	// Cost is the bcrypt cost, 0 for other formats.
+	Cost int64
+	// Rounds is the SHA-crypt rounds, 0 for other formats.
+	Rounds int64
+	// Argon2Memory, Argon2Time and Argon2Parallelism are the argon2id
+	// parameters, 0 for other formats.
+	Argon2Memory      int64
+	Argon2Time        int64
+	Argon2Parallelism int64
+	// ScryptN, ScryptR and ScryptP are the scrypt parameters, 0 for other
+	// formats.
+	ScryptN int64
+	ScryptR int64
+	ScryptP int64
+}
+
+// describeHash parses the algorithm, salt and cost parameters of hash. Hashes
+// in an unknown format are described with algorithm unknown.
+func describeHash(hash string) hashInfo {
+	info := hashInfo{Algorithm: algorithmUnknown}
+	if h, ok := detectHasher(hash); ok {
+		info.Algorithm = h.Name()
+	}
+
+	parts := strings.Split(hash, "$")
+	switch info.Algorithm {
+	case "apr1":
+		if len(parts) == 4 {
+			info.Salt = parts[2]
+		}
+	case "argon2id":
+		if len(parts) == 6 {
+			info.Salt = parts[4]
+			fmt.Sscanf(parts[3], "m=%d,t=%d,p=%d", &info.Argon2Memory, &info.Argon2Time, &info.Argon2Parallelism)
+		}
+	case "bcrypt":
+		if len(parts) == 4 && len(parts[3]) == bcryptSaltLength+31 {
+			info.Salt = parts[3][:bcryptSaltLength]
+			info.Cost, _ = strconv.ParseInt(parts[2], 10, 64)
+		}
+	case "pbkdf2_sha512":
+		if len(parts) == 5 {
+			info.Salt = parts[3]
+			info.Rounds, _ = strconv.ParseInt(parts[2], 10, 64)
+		}
+	case "scrypt":
+		ln := -1
+		if len(parts) == 5 && parts[1] == "scrypt" {
+			info.Salt = parts[3]
+			fmt.Sscanf(parts[2], "ln=%d,r=%d,p=%d", &ln, &info.ScryptR, &info.ScryptP)
+		} else if len(parts) == 4 && parts[1] == "7" && len(parts[2]) > 11 {
+			info.Salt = parts[2][11:]
+			r, _ := cryptDecodeUint30(parts[2][1:6])
+			p, _ := cryptDecodeUint30(parts[2][6:11])
+			ln, info.ScryptR, info.ScryptP = strings.IndexByte(validSaltChars, parts[2][0]), int64(r), int64(p)
+		}
+		if ln >= 0 && ln <= 62 {
+			info.ScryptN = 1 << ln
+		}
+	case "sha256_crypt", "sha512":
+		info.Rounds = shaCryptDefaultRounds
+		if len(parts) == 5 && strings.HasPrefix(parts[2], "rounds=") {
+			info.Rounds, _ = strconv.ParseInt(strings.TrimPrefix(parts[2], "rounds="), 10, 64)
+			parts = append(parts[:2], parts[3:]...)
+		}
+		if len(parts) == 4 {
+			info.Salt = parts[2]
+		}
+	case "yescrypt":
+		if len(parts) == 5 {
+			info.Salt = parts[3]
+		}
+	case algorithmUnknown:
+		if len(parts) == 4 && parts[0] == "" && parts[1] == "1" {
+			info.Algorithm, info.Salt = algorithmMD5Crypt, parts[2]
+		} else if len(hash) == 13 && strings.Trim(hash, validSaltChars) == "" {
+			info.Algorithm, info.Salt = algorithmDESCrypt, hash[:2]
+		}
+	}
+	return info
+}
+
+// needsRehash reports whether a hash described by info falls short of the
+// settings in opts: it is in a format the provider does not produce, an
+// insecure or unselected algorithm, or uses a lower bcrypt cost, SHA-crypt
+// rounds, PBKDF2 iterations, or argon2id or scrypt parameters. Unset settings
+// in opts use the defaults.
+func (info hashInfo) needsRehash(opts HashOptions) bool {
+	if _, ok := hasherByName(info.Algorithm); !ok {
+		return true
+	}
+	if slices.Contains(insecureAlgorithms, info.Algorithm) || !opts.generates(info.Algorithm) {
+		return true
+	}
+	switch info.Algorithm {
+	case "bcrypt":
+		return info.Cost < cmp.Or(opts.BcryptCost, int64(bcrypt.DefaultCost))
+	case "argon2id":
+		return info.Argon2Memory < cmp.Or(opts.Argon2Memory, argon2DefaultMemory) ||
+			info.Argon2Time < cmp.Or(opts.Argon2Time, argon2DefaultTime) ||
+			info.Argon2Parallelism < cmp.Or(opts.Argon2Parallelism, argon2DefaultParallelism)
+	case "pbkdf2_sha512":
+		return info.Rounds < cmp.Or(opts.Pbkdf2Iterations, pbkdf2DefaultIterations)
+	case "scrypt":
+		return info.ScryptN < cmp.Or(opts.ScryptN, scryptDefaultN) ||
+			info.ScryptR < cmp.Or(opts.ScryptR, scryptDefaultR) ||
+			info.ScryptP < cmp.Or(opts.ScryptP, scryptDefaultP)
+	case "sha256_crypt":
+		return info.Rounds < cmp.Or(opts.Sha256Rounds, shaCryptDefaultRounds)
+	case "sha512":
+		return info.Rounds < cmp.Or(opts.Sha512Rounds, shaCryptDefaultRounds)
+	}
+	return false
+}
+
+// hasherByName returns the registered hasher with the given name.
+func hasherByName(name string) (Hasher, bool) {
+	for _, h := range hashers {
+		if h.Name() == name {
+			return h, true
+		}
+	}
+	return nil, false
+}
+
+// validateHashOptions validates opts and returns diagnostics for any invalid
+// settings.
+func validateHashOptions(opts HashOptions) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	// Validate salt based on legacy_hash setting
+	if err := validateSalt(opts.Salt, opts.LegacyHash); err != nil {
+		diags.AddError("Invalid Salt", err.Error())
+	}
+	if err := validateRounds(opts.Sha256Rounds); err != nil {
+		diags.AddAttributeError(path.Root("sha256_rounds"), "Invalid Rounds", err.Error())
+	}
+	if err := validateRounds(opts.Sha512Rounds); err != nil {
+		diags.AddAttributeError(path.Root("sha512_rounds"), "Invalid Rounds", err.Error())
+	}
+	if err := validateBcrypt(opts.BcryptCost, opts.BcryptVariant); err != nil {
+		diags.AddError("Invalid Bcrypt Settings", err.Error())
+	}
+	if err := validateBcryptSalt(opts.BcryptSalt); err != nil {
+		diags.AddError("Invalid Bcrypt Settings", err.Error())
+	}
+	if err := validateArgon2(opts.Argon2Memory, opts.Argon2Time, opts.Argon2Parallelism); err != nil {
+		diags.AddError("Invalid Argon2 Settings", err.Error())
+	}
+	if err := validatePbkdf2(opts.Pbkdf2Iterations); err != nil {
+		diags.AddAttributeError(path.Root("pbkdf2_iterations"), "Invalid PBKDF2 Settings", err.Error())
+	}
+	if err := validateScrypt(opts.ScryptN, opts.ScryptR, opts.ScryptP, opts.ScryptFormat); err != nil {
+		diags.AddError("Invalid Scrypt Settings", err.Error())
+	}
+	if err := validateAlgorithms(opts.Algorithms, false, false); err != nil {
+		diags.AddAttributeError(path.Root("algorithms"), "Invalid Algorithm", err.Error())
+	}
+	if err := validateHtdigest(opts.Username, opts.Realm); err != nil {
+		diags.AddError("Invalid Htdigest Settings", err.Error())
+	}
+
+	return diags
+}
+
+// validateConfigHashOptions validates the hash settings of a configuration in
+// ValidateConfig, so invalid values fail on validate and plan. Settings that
+// are checked together with a setting in related that is still unknown are
+// validated with the most permissive value of the unknown one.
+func validateConfigHashOptions(opts HashOptions, related map[string]attr.Value) diag.Diagnostics {
+	for name, value := range related {
+		if !value.IsUnknown() {
+			continue
+		}
+		switch name {
+		case "legacy_hash":
+			opts.LegacyHash = true
+		case "username", "realm":
+			opts.Username, opts.Realm = "", ""
+		case "argon2_parallelism":
+			opts.Argon2Parallelism = 1
+		}
+	}
+	return validateHashOptions(opts)
+}
+
+// generateHashes generates every registered hash of password and stores it in
+// the matching entry of values. Hashes that are not available or not selected
+// with opts are stored as null.
+func generateHashes(password string, opts HashOptions, values map[string]*types.String) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	for _, h := range hashers {
+		if !opts.generates(h.Name()) {
+			*values[h.Name()] = types.StringNull()
+			continue
+		}
+		hash, err := h.Generate(password, opts)
+		if err != nil {
+			diags.AddError("Hash Error", fmt.Sprintf("Failed to generate %s hash: %s", h.Name(), err))
+			return diags
+		}
+		*values[h.Name()] = hashValue(hash)
+	}
+
+	return diags
+}
+
+// refreshHashes keeps every stored hash in values that still verifies against
+// password and regenerates the ones that are missing or do not match. Hashes
+// that are not selected with opts are cleared.
+func refreshHashes(password string, opts HashOptions, values map[string]*types.String) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	for _, h := range hashers {
+		value := values[h.Name()]
+		if !opts.generates(h.Name()) {
+			*value = types.StringNull()
+			continue
+		}
+		if !value.IsNull() && !value.IsUnknown() && h.Verify(password, value.ValueString(), opts) {
+			continue
+		}
+		hash, err := h.Generate(password, opts)
+		if err != nil {
+			diags.AddError("Hash Error", fmt.Sprintf("Failed to regenerate %s hash: %s", h.Name(), err))
+			return diags
+		}
+		*value = hashValue(hash)
+	}
+
+	return diags
+}
+
+// hashValue returns hash as a string value, or null when hash is empty.
+func hashValue(hash string) types.String {
+	if hash == "" {
+		return types.StringNull()
+	}
+	return types.StringValue(hash)
+}
+
+type apr1Hasher struct{}
+
+func (apr1Hasher) Name() string { return "apr1" }
+
+func (apr1Hasher) Generate(password string, opts HashOptions) (string, error) {
+	return apr1.Hash(password, opts.Salt)
+}
+
+func (apr1Hasher) Verify(password, hash string, _ HashOptions) bool {
+	return verifyApr1(password, hash)
+}
+
+type argon2idHasher struct{}
+
+func (argon2idHasher) Name() string { return "argon2id" }
+
+func (argon2idHasher) Generate(password string, opts HashOptions) (string, error) {
+	return argon2idGenerate(password, opts.Salt, opts.Argon2Memory, opts.Argon2Time, opts.Argon2Parallelism)
+}
+
+func (argon2idHasher) Verify(password, hash string, _ HashOptions) bool {
+	return verifyArgon2id(password, hash)
+}
+
+type bcryptHasher struct{}
+
+func (bcryptHasher) Name() string { return "bcrypt" }
+
+func (bcryptHasher) Generate(password string, opts HashOptions) (string, error) {
+	return bcryptGenerate(password, opts.BcryptCost, opts.BcryptVariant, opts.BcryptSalt)
+}
+
+func (bcryptHasher) Verify(password, hash string, _ HashOptions) bool {
+	return verifyBcrypt(password, hash)
+}
+
+// htdigestHasher produces an htdigest file entry. It is only available when
+// both a username and a realm are set.
+type htdigestHasher struct{}
+
+func (htdigestHasher) Name() string { return "htdigest" }
+
+func (htdigestHasher) Generate(password string, opts HashOptions) (string, error) {
+	if opts.Username == "" || opts.Realm == "" {
+		return "", nil
+	}
+	return htdigestEntry(opts.Username, opts.Realm, password), nil
+}
+
+func (htdigestHasher) Verify(password, hash string, opts HashOptions) bool {
+	if opts.Username == "" || opts.Realm == "" {
+		return false
+	}
+	return verifyEqual(htdigestEntry(opts.Username, opts.Realm, password), hash)
+}
+
+type pbkdf2Sha512Hasher struct{}
+
+func (pbkdf2Sha512Hasher) Name() string { return "pbkdf2_sha512" }
+
+func (pbkdf2Sha512Hasher) Generate(password string, opts HashOptions) (string, error) {
+	return pbkdf2Generate(password, opts.Salt, opts.Pbkdf2Iterations)
+}
+
+func (pbkdf2Sha512Hasher) Verify(password, hash string, _ HashOptions) bool {
+	return verifyPbkdf2(password, hash)
+}
+
+type scryptHasher struct{}
+
+func (scryptHasher) Name() string { return "scrypt" }
+
+func (scryptHasher) Generate(password string, opts HashOptions) (string, error) {
+	return scryptGenerate(password, opts.Salt, opts.ScryptN, opts.ScryptR, opts.ScryptP, opts.ScryptFormat)
+}
+
+func (scryptHasher) Verify(password, hash string, _ HashOptions) bool {
+	return verifyScrypt(password, hash)
+}
+
+type sha1Hasher struct{}
+
+func (sha1Hasher) Name() string { return "sha1" }
+
+func (sha1Hasher) Generate(password string, _ HashOptions) (string, error) {
+	return sha1Crypt(password), nil
+}
+
+func (sha1Hasher) Verify(password, hash string, _ HashOptions) bool {
+	return verifySha1(password, hash)
+}
+
+// sha256Hasher produces the legacy hex encoded digest. The salt is not part of
+// the output, so verification needs the configured salt.
+type sha256Hasher struct{}
+
+func (sha256Hasher) Name() string { return "sha256" }
+
+func (sha256Hasher) Generate(password string, opts HashOptions) (string, error) {
+	return sha256Digest(password, opts.Salt), nil
+}
+
+func (sha256Hasher) Verify(password, hash string, opts HashOptions) bool {
+	return verifyEqual(sha256Digest(password, opts.Salt), hash)
+}
+
+type sha256CryptHasher struct{}
+
+func (sha256CryptHasher) Name() string { return "sha256_crypt" }
+
+func (sha256CryptHasher) Generate(password string, opts HashOptions) (string, error) {
+	return sha256Crypt(password, opts.Salt, opts.Sha256Rounds), nil
+}
+
+func (sha256CryptHasher) Verify(password, hash string, _ HashOptions) bool {
+	return strings.HasPrefix(hash, "$5$") && verifySHACrypt(password, hash)
+}
+
+type sha512Hasher struct{}
+
+func (sha512Hasher) Name() string { return "sha512" }
+
+func (sha512Hasher) Generate(password string, opts HashOptions) (string, error) {
+	return sha512Crypt(password, opts.Salt, opts.Sha512Rounds), nil
+}
+
+func (sha512Hasher) Verify(password, hash string, _ HashOptions) bool {
+	return strings.HasPrefix(hash, "$6$") && verifySHACrypt(password, hash)
+}
+
+type yescryptHasher struct{}
+
+func (yescryptHasher) Name() string { return "yescrypt" }
+
+func (yescryptHasher) Generate(password string, opts HashOptions) (string, error) {
+	return yescryptGenerate(password, opts.Salt)
+}
+
+func (yescryptHasher) Verify(password, hash string, _ HashOptions) bool {
+	return verifyYescrypt(password, hash)
+}
@@ -0,0 +1,342 @@
+package htpasswd
+
+import (
+	"os"
+	"os/exec"
+	"slices"
+	"testing"
+)
+
+func TestHashers_GenerateAndVerify(t *testing.T) {
+	opts := HashOptions{
+		Salt:              "saltySal",
+		BcryptCost:        4,
+		Argon2Memory:      1024,
+		Argon2Time:        1,
+		Argon2Parallelism: 1,
+		ScryptN:           1024,
+		Pbkdf2Iterations:  1000,
+		Username:          "alice",
+		Realm:             "private",
+	}
+
+	for _, h := range hashers {
+		t.Run(h.Name(), func(t *testing.T) {
+			hash, err := h.Generate("secret123", opts)
+			if err != nil {
+				t.Fatalf("Generate() error = %v", err)
+			}
+			if !h.Verify("secret123", hash, opts) {
+				t.Errorf("Verify() = false for generated hash %q", hash)
+			}
+			if h.Verify("wrong", hash, opts) {
+				t.Errorf("Verify() = true for wrong password and hash %q", hash)
+			}
+		})
+	}
+}
+
+func TestHashers_ModelsExposeEveryHash(t *testing.T) {
+	resourceValues := (&PasswordModel{}).hashValues()
+	ephemeralValues := (&PasswordEphemeralModel{}).hashValues()
+
+	for _, h := range hashers {
+		if _, ok := resourceValues[h.Name()]; !ok {
+			t.Errorf("PasswordModel does not expose hash %q", h.Name())
+		}
+		if _, ok := ephemeralValues[h.Name()]; !ok {
+			t.Errorf("PasswordEphemeralModel does not expose hash %q", h.Name())
+		}
+	}
+}
+
+func TestGenerateHashes_Algorithms(t *testing.T) {
+	var data PasswordModel
+	values := data.hashValues()
+	opts := HashOptions{Salt: "saltySal", BcryptCost: 4, Algorithms: []string{"apr1", "sha512"}}
+
+	if diags := generateHashes("password", opts, values); diags.HasError() {
+		t.Fatalf("generateHashes() diagnostics = %v", diags)
+	}
+	for name, value := range values {
+		if opts.generates(name) == value.IsNull() {
+			t.Errorf("%s = %s, selected %t", name, value, opts.generates(name))
+		}
+	}
+
+	opts.Algorithms = []string{"bcrypt"}
+	if diags := refreshHashes("password", opts, values); diags.HasError() {
+		t.Fatalf("refreshHashes() diagnostics = %v", diags)
+	}
+	if !data.Apr1.IsNull() || !data.Sha512.IsNull() || data.Bcrypt.IsNull() {
+		t.Errorf("refreshHashes() = apr1 %s, sha512 %s, bcrypt %s, want only bcrypt", data.Apr1, data.Sha512, data.Bcrypt)
+	}
+}
+
+func TestValidateAlgorithms(t *testing.T) {
+	tests := []struct {
+		algorithms   []string
+		denyInsecure bool
+		fips         bool
+		wantErr      bool
+	}{
+		{nil, true, false, false},
+		{nil, false, true, false},
+		{[]string{}, false, false, true},
+		{[]string{"bcrypt", "sha512"}, true, false, false},
+		{[]string{"md5"}, false, false, true},
+		{[]string{"apr1"}, false, false, false},
+		{[]string{"apr1"}, true, false, true},
+		{[]string{"pbkdf2_sha512", "sha256_crypt", "sha512"}, false, true, false},
+		{[]string{"sha512", "bcrypt"}, false, true, true},
+		{[]string{"sha1"}, false, true, true},
+	}
+
+	for _, tt := range tests {
+		if err := validateAlgorithms(tt.algorithms, tt.denyInsecure, tt.fips); (err != nil) != tt.wantErr {
+			t.Errorf("validateAlgorithms(%q, %t, %t) error = %v, wantErr %t", tt.algorithms, tt.denyInsecure, tt.fips, err, tt.wantErr)
+		}
+	}
+}
+
+func TestDetectHasher(t *testing.T) {
+	opts := HashOptions{
+		Salt:              "saltySal",
+		BcryptCost:        4,
+		Argon2Memory:      1024,
+		Argon2Time:        1,
+		Argon2Parallelism: 1,
+		ScryptN:           1024,
+		Pbkdf2Iterations:  1000,
+	}
+
+	for _, h := range hashers {
+		// Neither the legacy sha256 digest nor htdigest entries have a prefix.
+		if h.Name() == "sha256" || h.Name() == "htdigest" {
+			continue
+		}
+		hash, err := h.Generate("secret123", opts)
+		if err != nil {
+			t.Fatalf("%s: Generate() error = %v", h.Name(), err)
+		}
+		detected, ok := detectHasher(hash)
+		if !ok || detected.Name() != h.Name() {
+			t.Errorf("detectHasher(%q) = %v, %v, want %s", hash, detected, ok, h.Name())
+		}
+	}
+
+	if _, ok := detectHasher("5e884898da28047151d0e56f8dc6292773603d0d6aabbdd62a11ef721d1542d8"); ok {
+		t.Errorf("detectHasher() detected a hash without prefix")
+	}
+}
+
+func TestVerifiableHasherNames(t *testing.T) {
+	want := []string{"apr1", "argon2id", "bcrypt", "pbkdf2_sha512", "scrypt", "sha1", "sha256_crypt", "sha512", "yescrypt"}
+	if got := verifiableHasherNames(); !slices.Equal(got, want) {
+		t.Errorf("verifiableHasherNames() = %v, want %v", got, want)
+	}
+}
+
+func TestDescribeHash(t *testing.T) {
+	tests := []struct {
+		hash string
+		want hashInfo
+	}{
+		{"$apr1$saltySal$a2VqVYQs.Pn9WnYqB9arf.", hashInfo{Algorithm: "apr1", Salt: "saltySal"}},
+		{"$argon2id$v=19$m=65536,t=2,p=1$c29tZXNhbHQ$CTFhFdXPJO1aFaMaO6Mm5c8y7cJHAph8ArZWb2GRPPc", hashInfo{Algorithm: "argon2id", Salt: "c29tZXNhbHQ", Argon2Memory: 65536, Argon2Time: 2, Argon2Parallelism: 1}},
+		{"$2y$05$CCCCCCCCCCCCCCCCCCCCC.E5YPO9kmyuRGyh0XouQYb4YMJKvyOeW", hashInfo{Algorithm: "bcrypt", Salt: "CCCCCCCCCCCCCCCCCCCCC.", Cost: 5}},
+		{"$pbkdf2-sha512$1000$c2FsdHlTYWw$1vzaMmUwjLZSnw9aYSF1jbndw/11DyEGDIpfUfdkCzNxp/46R1erci7UwdUiFU53vLcrMWKYuu0ez.iUtsLhgQ", hashInfo{Algorithm: "pbkdf2_sha512", Salt: "c2FsdHlTYWw", Rounds: 1000}},
+		{"$scrypt$ln=14,r=8,p=1$c2FsdHlTYWw$7f43sACRI+xY9sWXNFnuZOZSjTKeFyaM3hIJsIGQkqo", hashInfo{Algorithm: "scrypt", Salt: "c2FsdHlTYWw", ScryptN: 16384, ScryptR: 8, ScryptP: 1}},
+		{"$7$C6..../....saltySal$hvzBk0EYXkCKqLwZoYZvYNiIB8XbLM0XS9F0k46YGe8", hashInfo{Algorithm: "scrypt", Salt: "saltySal", ScryptN: 16384, ScryptR: 8, ScryptP: 1}},
+		{"{SHA}W6ph5Mm5Pz8GgiULbPgzG37mj9g=", hashInfo{Algorithm: "sha1"}},
+		{"$5$saltySal$4Ty5aUmBWGOEccaQocjg2RAQ6V3ISGcIXzqmeGQqsuC", hashInfo{Algorithm: "sha256_crypt", Salt: "saltySal", Rounds: 5000}},
+		{"$6$rounds=100000$12341234$gCCybFnOp4SRlwpV6fDDQv3uXM1s4JHhuaP3oSdN3Y5O0wJSfgLM3CJGgr/It3JlSv/QAiM11MkkzvUjXTHXm.", hashInfo{Algorithm: "sha512", Salt: "12341234", Rounds: 100000}},
+		{"$y$j9T$saltySal$IhSdlMOWhMvju7xEQ.Xx3c.t372QErgDynv0Zw7pkOB", hashInfo{Algorithm: "yescrypt", Salt: "saltySal"}},
+		{"$1$saltySal$qqd6jh5fQLnbMXOOJu5rS0", hashInfo{Algorithm: "md5_crypt", Salt: "saltySal"}},
+		{"abJnggxhB/yWI", hashInfo{Algorithm: "des_crypt", Salt: "ab"}},
+		{"plaintext password", hashInfo{Algorithm: "unknown"}},
+	}
+
+	for _, tt := range tests {
+		if got := describeHash(tt.hash); got != tt.want {
+			t.Errorf("describeHash(%q) = %+v, want %+v", tt.hash, got, tt.want)
+		}
+	}
+}
+
+func TestHashInfo_NeedsRehash(t *testing.T) {
+	tests := []struct {
+		info hashInfo
+		opts HashOptions
+		want bool
+	}{
+		{hashInfo{Algorithm: "bcrypt", Cost: 10}, HashOptions{}, false},
+		{hashInfo{Algorithm: "bcrypt", Cost: 5}, HashOptions{}, true},
+		{hashInfo{Algorithm: "bcrypt", Cost: 5}, HashOptions{BcryptCost: 5}, false},
+		{hashInfo{Algorithm: "bcrypt", Cost: 12}, HashOptions{Algorithms: fipsAlgorithms}, true},
+		{hashInfo{Algorithm: "sha512", Rounds: 5000}, HashOptions{}, false},
+		{hashInfo{Algorithm: "sha512", Rounds: 5000}, HashOptions{Sha512Rounds: 10000}, true},
+		{hashInfo{Algorithm: "sha256_crypt", Rounds: 1000}, HashOptions{}, true},
+		{hashInfo{Algorithm: "pbkdf2_sha512", Rounds: 1000}, HashOptions{}, true},
+		{hashInfo{Algorithm: "pbkdf2_sha512", Rounds: 1000}, HashOptions{Pbkdf2Iterations: 1000}, false},
+		{hashInfo{Algorithm: "argon2id", Argon2Memory: 65536, Argon2Time: 3, Argon2Parallelism: 4}, HashOptions{}, false},
+		{hashInfo{Algorithm: "argon2id", Argon2Memory: 65536, Argon2Time: 2, Argon2Parallelism: 4}, HashOptions{}, true},
+		{hashInfo{Algorithm: "argon2id", Argon2Memory: 19456, Argon2Time: 3, Argon2Parallelism: 4}, HashOptions{}, true},
+		{hashInfo{Algorithm: "argon2id", Argon2Memory: 65536, Argon2Time: 3, Argon2Parallelism: 1}, HashOptions{}, true},
+		{hashInfo{Algorithm: "argon2id", Argon2Memory: 19456, Argon2Time: 2, Argon2Parallelism: 1}, HashOptions{Argon2Memory: 19456, Argon2Time: 2, Argon2Parallelism: 1}, false},
+		{hashInfo{Algorithm: "scrypt", ScryptN: 32768, ScryptR: 8, ScryptP: 1}, HashOptions{}, false},
+		{hashInfo{Algorithm: "scrypt", ScryptN: 16384, ScryptR: 8, ScryptP: 1}, HashOptions{}, true},
+		{hashInfo{Algorithm: "scrypt", ScryptN: 32768, ScryptR: 4, ScryptP: 1}, HashOptions{}, true},
+		{hashInfo{Algorithm: "scrypt", ScryptN: 32768, ScryptR: 8, ScryptP: 1}, HashOptions{ScryptP: 2}, true},
+		{hashInfo{Algorithm: "scrypt", ScryptN: 16384, ScryptR: 8, ScryptP: 1}, HashOptions{ScryptN: 16384}, false},
+		{hashInfo{Algorithm: "scrypt"}, HashOptions{}, true},
+		{hashInfo{Algorithm: "apr1"}, HashOptions{}, true},
+		{hashInfo{Algorithm: "sha1"}, HashOptions{}, true},
+		{hashInfo{Algorithm: "md5_crypt"}, HashOptions{}, true},
+		{hashInfo{Algorithm: "unknown"}, HashOptions{}, true},
+	}
+
+	for _, tt := range tests {
+		if got := tt.info.needsRehash(tt.opts); got != tt.want {
+			t.Errorf("%+v.needsRehash(%+v) = %t, want %t", tt.info, tt.opts, got, tt.want)
+		}
+	}
+}
+
+func TestVerify_OversizedParameters(t *testing.T) {
+	// Cost parameters above the verification limits are refused before any
+	// memory is allocated or any rounds are computed, instead of panicking or
+	// exhausting memory and time.
+	cases := []struct {
+		name string
+		hash string
+	}{
+		{"scrypt ln", "$scrypt$ln=40,r=8,p=1$c2FsdHlTYWw$7f43sACRI+xY9sWXNFnuZOZSjTKeFyaM3hIJsIGQkqo"},
+		{"scrypt memory", "$scrypt$ln=24,r=64,p=1$c2FsdHlTYWw$7f43sACRI+xY9sWXNFnuZOZSjTKeFyaM3hIJsIGQkqo"},
+		{"scrypt r", "$scrypt$ln=1,r=2147483647,p=1$c2FsdHlTYWw$7f43sACRI+xY9sWXNFnuZOZSjTKeFyaM3hIJsIGQkqo"},
+		{"scrypt p", "$scrypt$ln=14,r=8,p=1000000$c2FsdHlTYWw$7f43sACRI+xY9sWXNFnuZOZSjTKeFyaM3hIJsIGQkqo"},
+		{"scrypt $7$ ln", "$7$c6..../....saltySal$hvzBk0EYXkCKqLwZoYZvYNiIB8XbLM0XS9F0k46YGe8"},
+		{"argon2id memory", "$argon2id$v=19$m=4294967295,t=2,p=1$c29tZXNhbHQ$CTFhFdXPJO1aFaMaO6Mm5c8y7cJHAph8ArZWb2GRPPc"},
+		{"argon2id time", "$argon2id$v=19$m=1024,t=4294967295,p=1$c29tZXNhbHQ$CTFhFdXPJO1aFaMaO6Mm5c8y7cJHAph8ArZWb2GRPPc"},
+		{"bcrypt cost", "$2a$31$CCCCCCCCCCCCCCCCCCCCC.E5YPO9kmyuRGyh0XouQYb4YMJKvyOeW"},
+		{"sha256_crypt rounds", "$5$rounds=999999999$saltySal$EVSFEd9pwEGvE7v0ceuYb5xLIcMCKvju5xmNm1ivjQ5"},
+		{"sha512_crypt rounds", "$6$rounds=999999999$12341234$gCCybFnOp4SRlwpV6fDDQv3uXM1s4JHhuaP3oSdN3Y5O0wJSfgLM3CJGgr/It3JlSv/QAiM11MkkzvUjXTHXm."},
+		{"pbkdf2_sha512 iterations", "$pbkdf2-sha512$2147483647$c2FsdHlTYWw$1vzaMmUwjLZSnw9aYSF1jbndw/11DyEGDIpfUfdkCzNxp/46R1erci7UwdUiFU53vLcrMWKYuu0ez.iUtsLhgQ"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			hasher, ok := detectHasher(tc.hash)
+			if !ok {
+				t.Fatalf("detectHasher(%q) found no algorithm", tc.hash)
+			}
+			if hasher.Verify("password", tc.hash, HashOptions{}) {
+				t.Errorf("verified %q, want false", tc.hash)
+			}
+		})
+	}
+}
+
+func TestHtdigestHasher(t *testing.T) {
+	// Expected value is the format written by Apache htdigest:
+	//   printf 'alice:private:secret123' | md5sum
+	hash, err := htdigestHasher{}.Generate("secret123", HashOptions{Username: "alice", Realm: "private"})
+	if err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+	if want := "alice:private:4577980e2c512b92b0c67633674f8f07"; hash != want {
+		t.Errorf("Generate() = %q, want %q", hash, want)
+	}
+
+	hash, err = htdigestHasher{}.Generate("secret123", HashOptions{})
+	if err != nil || hash != "" {
+		t.Errorf("Generate() without username and realm = %q, %v, want empty hash", hash, err)
+	}
+}
+
+func TestPbkdf2Sha512Hasher(t *testing.T) {
+	// Expected values are the passlib pbkdf2_sha512 format:
+	//   pbkdf2_sha512.using(salt=salt, rounds=1000).hash("password")
+	// with salt b"saltySaltySalty!", and hashlib.sha256(b"saltySal").digest()[:16]
+	// for the salt shorter than 128 bits.
+	tests := []struct {
+		salt string
+		want string
+	}{
+		{"saltySaltySalty!", "$pbkdf2-sha512$1000$c2FsdHlTYWx0eVNhbHR5IQ$AymnEaYmCxgh5/.Wsu9MsIhDmg4oUtYkoxX2waemT9WjISC.W5qswplP0idnNEsTiC1kGOr3./xy/vwDKJI0sQ"},
+		{"saltySal", "$pbkdf2-sha512$1000$drecY.N0qcSE5j5DapSZ/g$0QjoaS4Ainm/HCrEBm/OXC8jsOEBfo3.XKG8sJacbBpUWu1jMQXv7r6OaXSecYXV7W/mGPIwVJ8rH6y8t7i/6g"},
+	}
+	for _, tt := range tests {
+		hash, err := pbkdf2Sha512Hasher{}.Generate("password", HashOptions{Salt: tt.salt, Pbkdf2Iterations: 1000})
+		if err != nil {
+			t.Fatalf("Generate(%q) error = %v", tt.salt, err)
+		}
+		if hash != tt.want {
+			t.Errorf("Generate(%q) = %q, want %q", tt.salt, hash, tt.want)
+		}
+	}
+
+	// Hashes of other tools with salts shorter than 128 bits still verify:
+	//   pbkdf2_sha512.using(salt=b"saltySal", rounds=1000).hash("password")
+	if hash := "$pbkdf2-sha512$1000$c2FsdHlTYWw$1vzaMmUwjLZSnw9aYSF1jbndw/11DyEGDIpfUfdkCzNxp/46R1erci7UwdUiFU53vLcrMWKYuu0ez.iUtsLhgQ"; !(pbkdf2Sha512Hasher{}).Verify("password", hash, HashOptions{}) {
+		t.Errorf("Verify(%q) = false, want true", hash)
+	}
+}
+
+// TestFIPSAlgorithms_FIPS140Only generates and verifies the FIPS approved
+// hashes in a test binary running with GODEBUG=fips140=only, which refuses
+// non-approved parameters such as PBKDF2 salts shorter than 128 bits.
+func TestFIPSAlgorithms_FIPS140Only(t *testing.T) {
+	if os.Getenv("HTPASSWD_TEST_FIPS140_ONLY") == "" {
+		cmd := exec.Command(os.Args[0], "-test.run=^TestFIPSAlgorithms_FIPS140Only$", "-test.v")
+		cmd.Env = append(os.Environ(), "HTPASSWD_TEST_FIPS140_ONLY=1", "GODEBUG=fips140=only")
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("GODEBUG=fips140=only: %v\n%s", err, out)
+		}
+		return
+	}
+
+	for _, name := range fipsAlgorithms {
+		for _, salt := range []string{"", "saltySal"} {
+			hasher, _ := hasherByName(name)
+			hash, err := hasher.Generate("secret123", HashOptions{Salt: salt, Pbkdf2Iterations: 1000})
+			if err != nil {
+				t.Fatalf("%s: Generate() with salt %q error = %v", name, salt, err)
+			}
+			if !hasher.Verify("secret123", hash, HashOptions{}) {
+				t.Errorf("%s: Verify(%q) = false, want true", name, hash)
+			}
+		}
+	}
+}
+
+func TestDeriveSalt(t *testing.T) {
+	// Expected values are the HMAC-SHA256 bytes mapped onto the alphabet:
+	//   hmac.new(key, context, hashlib.sha256).digest()[:8]
+	tests := []struct {
+		key, context, want string
+	}{
+		{"correct horse battery staple", "alice", "lvubSiXh"},
+		{"correct horse battery staple", "bob", "Z/WvxbQZ"},
+	}
+
+	for _, tt := range tests {
+		if got := deriveSalt(tt.key, tt.context); got != tt.want {
+			t.Errorf("deriveSalt(%q, %q) = %q, want %q", tt.key, tt.context, got, tt.want)
+		}
+		if err := validateSalt(deriveSalt(tt.key, tt.context), false); err != nil {
+			t.Errorf("deriveSalt(%q, %q) is not a valid salt: %v", tt.key, tt.context, err)
+		}
+	}
+}
+
+func TestDeriveBcryptSalt(t *testing.T) {
+	// Expected value is the bcrypt base64 encoding of
+	//   hmac.new(key, b"bcrypt:" + context, hashlib.sha256).digest()[:16]
+	salt := deriveBcryptSalt("correct horse battery staple", "alice")
+	if want := "VtuFgIInBH9c2ZrOxu01je"; salt != want {
+		t.Errorf("deriveBcryptSalt() = %q, want %q", salt, want)
+	}
+	if err := validateBcryptSalt(salt); err != nil {
+		t.Errorf("deriveBcryptSalt() is not a valid bcrypt salt: %v", err)
+	}
+}
@@ -2,21 +2,138 @@ package htpasswd
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
 
 	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
 	"github.com/hashicorp/terraform-plugin-framework/ephemeral"
+	"github.com/hashicorp/terraform-plugin-framework/function"
+	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/provider"
 	"github.com/hashicorp/terraform-plugin-framework/provider/schema"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/types"
 )
 
 var _ provider.Provider = &HtpasswdProvider{}
 var _ provider.ProviderWithEphemeralResources = &HtpasswdProvider{}
+var _ provider.ProviderWithFunctions = &HtpasswdProvider{}
 
 type HtpasswdProvider struct {
 	version string
 }
 
+type HtpasswdProviderModel struct {
+	BcryptCost             types.Int64  `tfsdk:"bcrypt_cost"`
+	Sha256Rounds           types.Int64  `tfsdk:"sha256_rounds"`
+	Sha512Rounds           types.Int64  `tfsdk:"sha512_rounds"`
+	Algorithms             types.Set    `tfsdk:"algorithms"`
+	DenyInsecureAlgorithms types.Bool   `tfsdk:"deny_insecure_algorithms"`
+	FIPSMode               types.Bool   `tfsdk:"fips_mode"`
+	SaltDerivationKey      types.String `tfsdk:"salt_derivation_key"`
+}
+
+// providerConfig is the provider configuration passed to the resources, data
+// sources and ephemeral resources that generate or check hashes.
+type providerConfig struct {
+	BcryptCost             int64
+	Sha256Rounds           int64
+	Sha512Rounds           int64
+	Algorithms             []string
+	DenyInsecureAlgorithms bool
+	FIPSMode               bool
+	SaltDerivationKey      string
+}
+
+// hashOptions returns opts with the provider defaults applied to the settings
+// left unset, and the hashes limited to the algorithms allowed by the
+// provider. A nil config leaves opts unchanged.
+func (c *providerConfig) hashOptions(opts HashOptions) HashOptions {
+	if c == nil {
+		return opts
+	}
+	if opts.BcryptCost == 0 {
+		opts.BcryptCost = c.BcryptCost
+	}
+	if opts.Sha256Rounds == 0 {
+		opts.Sha256Rounds = c.Sha256Rounds
+	}
+	if opts.Sha512Rounds == 0 {
+		opts.Sha512Rounds = c.Sha512Rounds
+	}
+	if opts.Algorithms == nil {
+		opts.Algorithms = c.Algorithms
+	}
+	return opts
+}
+
+// validate returns diagnostics for the settings of opts refused by the
+// provider configuration.
+func (c *providerConfig) validate(opts HashOptions) diag.Diagnostics {
+	var diags diag.Diagnostics
+	if c == nil {
+		return diags
+	}
+	if err := validateAlgorithms(opts.Algorithms, c.DenyInsecureAlgorithms, c.FIPSMode); err != nil {
+		diags.AddAttributeError(path.Root("algorithms"), "Invalid Algorithm", err.Error())
+	}
+	return diags
+}
+
+// validateAlgorithm returns an error when the provider configuration refuses
+// to hash the passwords of htpasswd_file and htpasswd_users with algorithm.
+func (c *providerConfig) validateAlgorithm(algorithm string) error {
+	if c == nil || !c.FIPSMode {
+		return nil
+	}
+	return validateAlgorithms([]string{algorithm}, false, true)
+}
+
+// validateVerify returns an error when the provider configuration refuses to
+// verify hashes of algorithm, such as an import ID or the hash of
+// htpasswd_verify.
+func (c *providerConfig) validateVerify(algorithm string) error {
+	if c == nil || !c.FIPSMode || !slices.Contains(md5SHA1Algorithms, algorithm) {
+		return nil
+	}
+	return fmt.Errorf("%s hashes are built on MD5 or SHA-1 and are not verified under fips_mode", algorithm)
+}
+
+// deriveSalt returns the salt derived from the salt_context of a resource with
+// the provider salt_derivation_key.
+func (c *providerConfig) deriveSalt(context string) (string, error) {
+	if c == nil || c.SaltDerivationKey == "" {
+		return "", errors.New("salt_context requires the provider salt_derivation_key to be set")
+	}
+	if context == "" {
+		return "", errors.New("salt_context must not be empty")
+	}
+	return deriveSalt(c.SaltDerivationKey, context), nil
+}
+
+// algorithms returns the algorithms computed by default: the configured
+// algorithms, or all of them without the insecure ones when those are denied
+// and without the ones that are not FIPS approved in FIPS mode.
+func (m *HtpasswdProviderModel) algorithms(configured []string) []string {
+	if configured != nil || (!m.DenyInsecureAlgorithms.ValueBool() && !m.FIPSMode.ValueBool()) {
+		return configured
+	}
+	var algorithms []string
+	for _, name := range hasherNames() {
+		if m.DenyInsecureAlgorithms.ValueBool() && slices.Contains(insecureAlgorithms, name) {
+			continue
+		}
+		if m.FIPSMode.ValueBool() && !slices.Contains(fipsAlgorithms, name) {
+			continue
+		}
+		algorithms = append(algorithms, name)
+	}
+	return algorithms
+}
+
 func New(version string) func() provider.Provider {
 	return func() provider.Provider {
 		return &HtpasswdProvider{
@@ -31,20 +148,100 @@ func (p *HtpasswdProvider) Metadata(_ context.Context, _ provider.MetadataReques
 }
 
 func (p *HtpasswdProvider) Schema(_ context.Context, _ provider.SchemaRequest, resp *provider.SchemaResponse) {
-	resp.Schema = schema.Schema{}
+	resp.Schema = schema.Schema{
+		Attributes: map[string]schema.Attribute{
+			"bcrypt_cost": schema.Int64Attribute{
+				Optional:    true,
+				Description: "Default cost factor for bcrypt hashes (4-31), used when a resource does not set bcrypt_cost. Defaults to 10.",
+			},
+			"sha256_rounds": schema.Int64Attribute{
+				Optional:    true,
+				Description: "Default number of rounds for sha256_crypt hashes (1000-999999999), used when a resource does not set sha256_rounds. Defaults to 5000.",
+			},
+			"sha512_rounds": schema.Int64Attribute{
+				Optional:    true,
+				Description: "Default number of rounds for sha512 hashes (1000-999999999), used when a resource does not set sha512_rounds. Defaults to 5000.",
+			},
+			"algorithms": schema.SetAttribute{
+				Optional:    true,
+				ElementType: types.StringType,
+				Description: "Hashes computed by htpasswd_password, htpasswd_generated_password and the htpasswd_password ephemeral resource: " + strings.Join(hasherNames(), ", ") + ". Hashes of other algorithms are null. Defaults to all.",
+			},
+			"deny_insecure_algorithms": schema.BoolAttribute{
+				Optional:    true,
+				Description: "When true, the insecure " + strings.Join(insecureAlgorithms, ", ") + " hashes are not computed and can not be selected in algorithms.",
+			},
+			"fips_mode": schema.BoolAttribute{
+				Optional:    true,
+				Description: "When true, only the FIPS approved " + strings.Join(fipsAlgorithms, ", ") + " hashes are computed. Other algorithms can not be selected in algorithms, and htpasswd_file and htpasswd_users refuse them. htpasswd_verify and imports refuse apr1 and sha1 hashes. Provider functions are not affected.",
+			},
+			"salt_derivation_key": schema.StringAttribute{
+				Optional:    true,
+				Sensitive:   true,
+				Description: "Secret key used to derive the salt of htpasswd_password resources and ephemeral resources that set salt_context, as the HMAC-SHA256 of salt_context. The same key and salt_context always derive the same salt.",
+			},
+		},
+	}
 }
 
-func (p *HtpasswdProvider) Configure(_ context.Context, _ provider.ConfigureRequest, _ *provider.ConfigureResponse) {
+func (p *HtpasswdProvider) Configure(ctx context.Context, req provider.ConfigureRequest, resp *provider.ConfigureResponse) {
+	var data HtpasswdProviderModel
+
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if err := validateBcrypt(data.BcryptCost.ValueInt64(), ""); err != nil {
+		resp.Diagnostics.AddAttributeError(path.Root("bcrypt_cost"), "Invalid Bcrypt Settings", err.Error())
+	}
+	if err := validateRounds(data.Sha256Rounds.ValueInt64()); err != nil {
+		resp.Diagnostics.AddAttributeError(path.Root("sha256_rounds"), "Invalid Rounds", err.Error())
+	}
+	if err := validateRounds(data.Sha512Rounds.ValueInt64()); err != nil {
+		resp.Diagnostics.AddAttributeError(path.Root("sha512_rounds"), "Invalid Rounds", err.Error())
+	}
+
+	if !data.SaltDerivationKey.IsNull() && data.SaltDerivationKey.ValueString() == "" {
+		resp.Diagnostics.AddAttributeError(path.Root("salt_derivation_key"), "Invalid Salt Derivation Key", "salt_derivation_key must not be empty")
+	}
+
+	algorithms := algorithmsValue(data.Algorithms)
+	if err := validateAlgorithms(algorithms, data.DenyInsecureAlgorithms.ValueBool(), data.FIPSMode.ValueBool()); err != nil {
+		resp.Diagnostics.AddAttributeError(path.Root("algorithms"), "Invalid Algorithm", err.Error())
+	}
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	config := &providerConfig{
+		BcryptCost:             data.BcryptCost.ValueInt64(),
+		Sha256Rounds:           data.Sha256Rounds.ValueInt64(),
+		Sha512Rounds:           data.Sha512Rounds.ValueInt64(),
+		Algorithms:             data.algorithms(algorithms),
+		DenyInsecureAlgorithms: data.DenyInsecureAlgorithms.ValueBool(),
+		FIPSMode:               data.FIPSMode.ValueBool(),
+		SaltDerivationKey:      data.SaltDerivationKey.ValueString(),
+	}
+	resp.DataSourceData = config
+	resp.ResourceData = config
+	resp.EphemeralResourceData = config
 }
 
 func (p *HtpasswdProvider) Resources(_ context.Context) []func() resource.Resource {
 	return []func() resource.Resource{
 		NewPasswordResource,
+		NewGeneratedPasswordResource,
+		NewFileResource,
+		NewUsersResource,
 	}
 }
 
 func (p *HtpasswdProvider) DataSources(_ context.Context) []func() datasource.DataSource {
-	return nil
+	return []func() datasource.DataSource{
+		NewFileDataSource,
+		NewVerifyDataSource,
+	}
 }
 
 func (p *HtpasswdProvider) EphemeralResources(_ context.Context) []func() ephemeral.EphemeralResource {
@@ -52,3 +249,20 @@ func (p *HtpasswdProvider) EphemeralResources(_ context.Context) []func() epheme
 		NewPasswordEphemeral,
 	}
 }
+
+func (p *HtpasswdProvider) Functions(_ context.Context) []func() function.Function {
+	return []func() function.Function{
+		NewApr1Function,
+		NewArgon2idFunction,
+		NewBcryptFunction,
+		NewHtdigestFunction,
+		NewPbkdf2Sha512Function,
+		NewScryptFunction,
+		NewSha1Function,
+		NewSha256Function,
+		NewSha256CryptFunction,
+		NewSha512CryptFunction,
+		NewVerifyFunction,
+		NewYescryptFunction,
+	}
+}
@@ -1,10 +1,14 @@
 package htpasswd
 
 import (
+	"fmt"
+	"path/filepath"
+	"regexp"
 	"testing"
 
 	"github.com/hashicorp/terraform-plugin-framework/providerserver"
 	"github.com/hashicorp/terraform-plugin-go/tfprotov6"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
 )
 
 var testAccProtoV6ProviderFactories = map[string]func() (tfprotov6.ProviderServer, error){
@@ -14,3 +18,269 @@ var testAccProtoV6ProviderFactories = map[string]func() (tfprotov6.ProviderServe
 func TestProvider(t *testing.T) {
 	New("test")()
 }
+
+func TestAccProvider_Defaults(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: `
+provider "htpasswd" {
+	bcrypt_cost   = 5
+	sha512_rounds = 2000
+	algorithms    = ["bcrypt", "sha256_crypt", "sha512"]
+}
+
+resource "htpasswd_password" "test" {
+	password      = "password"
+	salt          = "saltySal"
+	sha256_rounds = 1000
+}
+`,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestMatchResourceAttr("htpasswd_password.test", "bcrypt", regexp.MustCompile(`^\$2a\$05\$`)),
+					resource.TestMatchResourceAttr("htpasswd_password.test", "sha256_crypt", regexp.MustCompile(`^\$5\$rounds=1000\$saltySal\$`)),
+					resource.TestMatchResourceAttr("htpasswd_password.test", "sha512", regexp.MustCompile(`^\$6\$rounds=2000\$saltySal\$`)),
+					resource.TestCheckNoResourceAttr("htpasswd_password.test", "apr1"),
+					resource.TestCheckNoResourceAttr("htpasswd_password.test", "argon2id"),
+					resource.TestCheckNoResourceAttr("htpasswd_password.test", "sha1"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccProvider_DenyInsecureAlgorithms(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: `
+provider "htpasswd" {
+	deny_insecure_algorithms = true
+}
+
+resource "htpasswd_generated_password" "test" {
+	length   = 16
+	username = "alice"
+	realm    = "private"
+}
+`,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttrSet("htpasswd_generated_password.test", "bcrypt"),
+					resource.TestCheckResourceAttrSet("htpasswd_generated_password.test", "yescrypt"),
+					resource.TestCheckNoResourceAttr("htpasswd_generated_password.test", "apr1"),
+					resource.TestCheckNoResourceAttr("htpasswd_generated_password.test", "htdigest"),
+					resource.TestCheckNoResourceAttr("htpasswd_generated_password.test", "sha1"),
+					resource.TestCheckNoResourceAttr("htpasswd_generated_password.test", "sha256"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccProvider_FIPSMode(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: `
+provider "htpasswd" {
+	fips_mode = true
+}
+
+resource "htpasswd_password" "test" {
+	password          = "password"
+	salt              = "saltySal"
+	pbkdf2_iterations = 1000
+}
+
+resource "htpasswd_users" "test" {
+	users = {
+		alice = "secret123"
+	}
+	algorithm = "sha512"
+}
+`,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("htpasswd_password.test", "pbkdf2_sha512", "$pbkdf2-sha512$1000$drecY.N0qcSE5j5DapSZ/g$0QjoaS4Ainm/HCrEBm/OXC8jsOEBfo3.XKG8sJacbBpUWu1jMQXv7r6OaXSecYXV7W/mGPIwVJ8rH6y8t7i/6g"),
+					resource.TestMatchResourceAttr("htpasswd_password.test", "sha256_crypt", regexp.MustCompile(`^\$5\$saltySal\$`)),
+					resource.TestMatchResourceAttr("htpasswd_password.test", "sha512", regexp.MustCompile(`^\$6\$saltySal\$`)),
+					resource.TestCheckNoResourceAttr("htpasswd_password.test", "apr1"),
+					resource.TestCheckNoResourceAttr("htpasswd_password.test", "bcrypt"),
+					resource.TestCheckNoResourceAttr("htpasswd_password.test", "sha1"),
+					resource.TestCheckNoResourceAttr("htpasswd_password.test", "sha256"),
+					resource.TestMatchResourceAttr("htpasswd_users.test", "htpasswd_content", regexp.MustCompile(`^alice:\$6\$[./0-9A-Za-z]{8}\$[^\n]+\n$`)),
+				),
+			},
+		},
+	})
+}
+
+func TestAccProvider_FIPSModeRefused(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: `
+provider "htpasswd" {
+	fips_mode  = true
+	algorithms = ["sha512", "apr1"]
+}
+
+resource "htpasswd_password" "test" {
+	password = "password"
+}
+`,
+				ExpectError: regexp.MustCompile(`apr1 is not FIPS approved and refused by fips_mode`),
+			},
+			{
+				Config: `
+provider "htpasswd" {
+	fips_mode = true
+}
+
+resource "htpasswd_password" "test" {
+	password   = "password"
+	algorithms = ["sha1"]
+}
+`,
+				ExpectError: regexp.MustCompile(`sha1 is not FIPS approved and refused by fips_mode`),
+			},
+			{
+				Config: `
+provider "htpasswd" {
+	fips_mode = true
+}
+
+resource "htpasswd_users" "test" {
+	users = {
+		alice = "secret123"
+	}
+}
+`,
+				ExpectError: regexp.MustCompile(`bcrypt is not FIPS approved`),
+			},
+			{
+				Config: fmt.Sprintf(`
+provider "htpasswd" {
+	fips_mode = true
+}
+
+resource "htpasswd_file" "test" {
+	path   = %q
+	format = "htdigest"
+	realm  = "private"
+
+	user {
+		username = "alice"
+		password = "secret123"
+	}
+}
+`, filepath.Join(t.TempDir(), ".htdigest")),
+				PlanOnly:    true,
+				ExpectError: regexp.MustCompile(`htdigest is not FIPS approved`),
+			},
+			{
+				Config: fmt.Sprintf(`
+provider "htpasswd" {
+	fips_mode = true
+}
+
+resource "htpasswd_file" "test" {
+	path = %q
+
+	user {
+		username = "alice"
+		password = "secret123"
+	}
+}
+`, filepath.Join(t.TempDir(), ".htpasswd")),
+				PlanOnly:    true,
+				ExpectError: regexp.MustCompile(`bcrypt is not FIPS approved`),
+			},
+		},
+	})
+}
+
+func TestAccProvider_FIPSModeImport(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: `
+provider "htpasswd" {
+	fips_mode = true
+}
+
+resource "htpasswd_password" "test" {
+	password = "password"
+}
+`,
+				ResourceName:       "htpasswd_password.test",
+				ImportState:        true,
+				ImportStateId:      "{SHA}W6ph5Mm5Pz8GgiULbPgzG37mj9g=",
+				ImportStatePersist: true,
+				ExpectError:        regexp.MustCompile(`sha1 hashes are built on MD5 or SHA-1`),
+			},
+		},
+	})
+}
+
+func TestAccProvider_InvalidConfig(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: `
+provider "htpasswd" {
+	deny_insecure_algorithms = true
+	algorithms               = ["bcrypt", "sha1"]
+}
+
+resource "htpasswd_password" "test" {
+	password = "password"
+}
+`,
+				ExpectError: regexp.MustCompile(`sha1 is insecure and denied by deny_insecure_algorithms`),
+			},
+			{
+				Config: `
+provider "htpasswd" {
+	deny_insecure_algorithms = true
+}
+
+resource "htpasswd_password" "test" {
+	password   = "password"
+	algorithms = ["apr1"]
+}
+`,
+				ExpectError: regexp.MustCompile(`apr1 is insecure and denied by deny_insecure_algorithms`),
+			},
+			{
+				Config: `
+provider "htpasswd" {
+	algorithms = ["md5"]
+}
+
+resource "htpasswd_password" "test" {
+	password = "password"
+}
+`,
+				ExpectError: regexp.MustCompile(`Invalid Algorithm`),
+			},
+			{
+				Config: `
+provider "htpasswd" {
+	bcrypt_cost = 3
+}
+
+resource "htpasswd_password" "test" {
+	password = "password"
+}
+`,
+				ExpectError: regexp.MustCompile(`bcrypt cost must be between 4 and 31`),
+			},
+		},
+	})
+}
@@ -0,0 +1,571 @@
+package htpasswd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringdefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+var _ resource.Resource = &FileResource{}
+var _ resource.ResourceWithValidateConfig = &FileResource{}
+var _ resource.ResourceWithModifyPlan = &FileResource{}
+var _ resource.ResourceWithConfigure = &FileResource{}
+
+// fileDefaultAlgorithm is the hash algorithm used for users without an
+// explicit algorithm.
+const fileDefaultAlgorithm = "bcrypt"
+
+// Supported file formats. htdigest files contain user:realm:digest lines for
+// Apache mod_auth_digest.
+const (
+	fileFormatHtpasswd = "htpasswd"
+	fileFormatHtdigest = "htdigest"
+)
+
+// fileDefaultPermission is the mode of the htpasswd file when none is
+// configured.
+const fileDefaultPermission = "0600"
+
+type FileResource struct {
+	config *providerConfig
+}
+
+type FileModel struct {
+	ID             types.String `tfsdk:"id"`
+	Path           types.String `tfsdk:"path"`
+	FilePermission types.String `tfsdk:"file_permission"`
+	Format         types.String `tfsdk:"format"`
+	Realm          types.String `tfsdk:"realm"`
+	Users          types.Set    `tfsdk:"user"`
+	Content        types.String `tfsdk:"content"`
+}
+
+// realm returns the realm of htdigest files, or an empty string for htpasswd
+// files.
+func (m *FileModel) realm() string {
+	if m.Format.ValueString() != fileFormatHtdigest {
+		return ""
+	}
+	return m.Realm.ValueString()
+}
+
+type FileUserModel struct {
+	Username  types.String `tfsdk:"username"`
+	Password  types.String `tfsdk:"password"`
+	Hash      types.String `tfsdk:"hash"`
+	Algorithm types.String `tfsdk:"algorithm"`
+}
+
+// htpasswdEntry is a single username and hash line of an htpasswd file.
+type htpasswdEntry struct {
+	Username string
+	Hash     string
+}
+
+func NewFileResource() resource.Resource {
+	return &FileResource{}
+}
+
+func (r *FileResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_file"
+}
+
+func (r *FileResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Manages an htpasswd file on disk",
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Computed:    true,
+				Description: "Resource identifier, the path of the file",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"path": schema.StringAttribute{
+				Required:    true,
+				Description: "Path of the htpasswd file. Missing parent directories are created.",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"file_permission": schema.StringAttribute{
+				Optional:    true,
+				Computed:    true,
+				Default:     stringdefault.StaticString(fileDefaultPermission),
+				Description: "Permissions of the file as an octal string, e.g. 0640. Default: 0600",
+			},
+			"format": schema.StringAttribute{
+				Optional:    true,
+				Computed:    true,
+				Default:     stringdefault.StaticString(fileFormatHtpasswd),
+				Description: "File format: htpasswd (user:hash) or htdigest (user:realm:digest). Default: htpasswd",
+			},
+			"realm": schema.StringAttribute{
+				Optional:    true,
+				Description: "Realm of htdigest entries. Required when format is htdigest.",
+			},
+			"content": schema.StringAttribute{
+				Computed:    true,
+				Description: "The rendered htpasswd file content",
+			},
+		},
+		Blocks: map[string]schema.Block{
+			"user": schema.SetNestedBlock{
+				Description: "A user entry of the file. Exactly one of password or hash must be set.",
+				NestedObject: schema.NestedBlockObject{
+					Attributes: map[string]schema.Attribute{
+						"username": schema.StringAttribute{
+							Required:    true,
+							Description: "The username",
+						},
+						"password": schema.StringAttribute{
+							Optional:    true,
+							Sensitive:   true,
+							Description: "The password to hash with algorithm",
+						},
+						"hash": schema.StringAttribute{
+							Optional:    true,
+							Description: "A pre-computed hash written to the file as-is. For htdigest files, the hex encoded MD5 digest.",
+						},
+						"algorithm": schema.StringAttribute{
+							Optional:    true,
+							Description: "Hash algorithm used for password in htpasswd files: " + strings.Join(fileAlgorithms(), ", ") + ". Default: " + fileDefaultAlgorithm,
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func (r *FileResource) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var data FileModel
+
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if !data.FilePermission.IsNull() && !data.FilePermission.IsUnknown() {
+		if _, err := parseFilePermission(data.FilePermission.ValueString()); err != nil {
+			resp.Diagnostics.AddAttributeError(path.Root("file_permission"), "Invalid File Permission", err.Error())
+		}
+	}
+
+	if !data.Format.IsNull() && !data.Format.IsUnknown() {
+		switch data.Format.ValueString() {
+		case fileFormatHtpasswd:
+			if !data.Realm.IsNull() {
+				resp.Diagnostics.AddAttributeError(path.Root("realm"), "Invalid Realm", "realm can only be set when format is htdigest")
+			}
+		case fileFormatHtdigest:
+			if data.Realm.IsNull() {
+				resp.Diagnostics.AddAttributeError(path.Root("realm"), "Invalid Realm", "realm is required when format is htdigest")
+			}
+		default:
+			resp.Diagnostics.AddAttributeError(path.Root("format"), "Invalid Format", fmt.Sprintf("format must be %s or %s, got %q", fileFormatHtpasswd, fileFormatHtdigest, data.Format.ValueString()))
+		}
+	}
+	if !data.Realm.IsNull() && !data.Realm.IsUnknown() {
+		if err := validateRealm(data.Realm.ValueString()); err != nil {
+			resp.Diagnostics.AddAttributeError(path.Root("realm"), "Invalid Realm", err.Error())
+		}
+	}
+
+	if data.Users.IsUnknown() {
+		return
+	}
+	var users []FileUserModel
+	resp.Diagnostics.Append(data.Users.ElementsAs(ctx, &users, false)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	seen := make(map[string]bool)
+	for _, user := range users {
+		if !user.Username.IsUnknown() {
+			username := user.Username.ValueString()
+			if err := validateUsername(username); err != nil {
+				resp.Diagnostics.AddAttributeError(path.Root("user"), "Invalid Username", err.Error())
+			}
+			if seen[username] {
+				resp.Diagnostics.AddAttributeError(path.Root("user"), "Duplicate Username", fmt.Sprintf("username %q is used by more than one user block", username))
+			}
+			seen[username] = true
+		}
+		if user.Password.IsUnknown() || user.Hash.IsUnknown() {
+			continue
+		}
+		if user.Password.IsNull() == user.Hash.IsNull() {
+			resp.Diagnostics.AddAttributeError(path.Root("user"), "Invalid User", fmt.Sprintf("user %q must set exactly one of password or hash", user.Username.ValueString()))
+		}
+		if !user.Hash.IsNull() && strings.ContainsAny(user.Hash.ValueString(), ":\r\n") {
+			resp.Diagnostics.AddAttributeError(path.Root("user"), "Invalid Hash", fmt.Sprintf("hash of user %q must not contain colons or line breaks", user.Username.ValueString()))
+		}
+		if !user.Algorithm.IsNull() && !user.Algorithm.IsUnknown() && !slices.Contains(fileAlgorithms(), user.Algorithm.ValueString()) {
+			resp.Diagnostics.AddAttributeError(path.Root("user"), "Invalid Algorithm", fmt.Sprintf("algorithm of user %q must be one of %s, got %q", user.Username.ValueString(), strings.Join(fileAlgorithms(), ", "), user.Algorithm.ValueString()))
+		}
+	}
+}
+
+// ModifyPlan refuses algorithms not allowed by the provider configuration and
+// plans an update when the file on disk, as recorded by Read, no longer
+// matches the configured users. Hashes in the file are compared by verifying
+// them against the configured passwords, so hashes with a random salt do not
+// cause a difference.
+func (r *FileResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	if req.Plan.Raw.IsNull() {
+		return
+	}
+
+	var plan FileModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() || plan.Users.IsUnknown() {
+		return
+	}
+
+	users, diags := fileUsers(ctx, plan.Users)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if !plan.Format.IsUnknown() && !plan.Realm.IsUnknown() {
+		resp.Diagnostics.Append(r.validate(users, plan.realm())...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+	}
+
+	if req.State.Raw.IsNull() || plan.Content.IsUnknown() {
+		return
+	}
+
+	var state FileModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	entries, complete := reuseEntries(users, parseHtpasswd(state.Content.ValueString()), plan.realm())
+	if complete && renderHtpasswd(entries) == state.Content.ValueString() {
+		return
+	}
+
+	resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("content"), types.StringUnknown())...)
+}
+
+func (r *FileResource) Configure(_ context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+	config, ok := req.ProviderData.(*providerConfig)
+	if !ok {
+		resp.Diagnostics.AddError("Unexpected Provider Data", fmt.Sprintf("Expected *providerConfig, got %T", req.ProviderData))
+		return
+	}
+	r.config = config
+}
+
+func (r *FileResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var data FileModel
+
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(r.write(ctx, &data, nil)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	data.ID = data.Path
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *FileResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var data FileModel
+
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Record the file as found on disk. ModifyPlan compares it with the
+	// configuration to detect out-of-band edits.
+	content, err := os.ReadFile(data.Path.ValueString())
+	if errors.Is(err, fs.ErrNotExist) {
+		resp.State.RemoveResource(ctx)
+		return
+	}
+	if err != nil {
+		resp.Diagnostics.AddError("File Error", fmt.Sprintf("Failed to read %s: %s", data.Path.ValueString(), err))
+		return
+	}
+	info, err := os.Stat(data.Path.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError("File Error", fmt.Sprintf("Failed to stat %s: %s", data.Path.ValueString(), err))
+		return
+	}
+	data.Content = types.StringValue(string(content))
+	data.FilePermission = types.StringValue(fmt.Sprintf("%04o", info.Mode().Perm()))
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *FileResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var data, state FileModel
+
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(r.write(ctx, &data, parseHtpasswd(state.Content.ValueString()))...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *FileResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var data FileModel
+
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if err := os.Remove(data.Path.ValueString()); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		resp.Diagnostics.AddError("File Error", fmt.Sprintf("Failed to delete %s: %s", data.Path.ValueString(), err))
+	}
+}
+
+// write renders the file for the users in data, reusing hashes from existing
+// that still verify, and writes it to disk.
+func (r *FileResource) write(ctx context.Context, data *FileModel, existing []htpasswdEntry) diag.Diagnostics {
+	users, diags := fileUsers(ctx, data.Users)
+	if diags.HasError() {
+		return diags
+	}
+
+	content, diags := renderUsers(users, existing, data.realm())
+	if diags.HasError() {
+		return diags
+	}
+
+	mode, err := parseFilePermission(data.FilePermission.ValueString())
+	if err != nil {
+		diags.AddAttributeError(path.Root("file_permission"), "Invalid File Permission", err.Error())
+		return diags
+	}
+	filename := data.Path.ValueString()
+	if err := os.MkdirAll(filepath.Dir(filename), 0o755); err != nil {
+		diags.AddError("File Error", fmt.Sprintf("Failed to create directory for %s: %s", filename, err))
+		return diags
+	}
+	if err := os.WriteFile(filename, []byte(content), mode); err != nil {
+		diags.AddError("File Error", fmt.Sprintf("Failed to write %s: %s", filename, err))
+		return diags
+	}
+	// WriteFile applies the umask and keeps the mode of existing files.
+	if err := os.Chmod(filename, mode); err != nil {
+		diags.AddError("File Error", fmt.Sprintf("Failed to set permissions of %s: %s", filename, err))
+		return diags
+	}
+
+	data.Content = types.StringValue(content)
+	return diags
+}
+
+// validate returns diagnostics for the algorithms of users refused by the
+// provider configuration.
+func (r *FileResource) validate(users []FileUserModel, realm string) diag.Diagnostics {
+	var diags diag.Diagnostics
+	for _, user := range users {
+		if !user.Hash.IsNull() || user.Algorithm.IsUnknown() {
+			continue
+		}
+		algorithm := user.algorithm()
+		if realm != "" {
+			algorithm = fileFormatHtdigest
+		}
+		if err := r.config.validateAlgorithm(algorithm); err != nil {
+			diags.AddAttributeError(path.Root("user"), "Invalid Algorithm", fmt.Sprintf("password of user %q: %s", user.Username.ValueString(), err))
+		}
+	}
+	return diags
+}
+
+// renderUsers renders the content for users, reusing hashes from existing that
+// still verify and generating the others. A non-empty realm renders htdigest
+// lines for the realm instead of htpasswd lines.
+func renderUsers(users []FileUserModel, existing []htpasswdEntry, realm string) (string, diag.Diagnostics) {
+	var diags diag.Diagnostics
+
+	entries, _ := reuseEntries(users, existing, realm)
+	for i, user := range users {
+		if entries[i].Hash != "" {
+			continue
+		}
+		hasher, ok := hasherByName(user.algorithm())
+		if !ok {
+			diags.AddAttributeError(path.Root("user"), "Invalid Algorithm", fmt.Sprintf("algorithm of user %q must be one of %s, got %q", user.Username.ValueString(), strings.Join(fileAlgorithms(), ", "), user.algorithm()))
+			return "", diags
+		}
+		salt, err := randomSalt(generatedSaltLength)
+		if err != nil {
+			diags.AddError("Salt Error", fmt.Sprintf("Failed to generate salt: %s", err))
+			return "", diags
+		}
+		hash, err := hasher.Generate(user.Password.ValueString(), HashOptions{Salt: salt})
+		if err != nil {
+			diags.AddError("Hash Error", fmt.Sprintf("Failed to generate %s hash for user %q: %s", hasher.Name(), user.Username.ValueString(), err))
+			return "", diags
+		}
+		entries[i].Hash = hash
+	}
+	return renderHtpasswd(entries), diags
+}
+
+// algorithm returns the configured hash algorithm or the default.
+func (u FileUserModel) algorithm() string {
+	if u.Algorithm.IsNull() || u.Algorithm.ValueString() == "" {
+		return fileDefaultAlgorithm
+	}
+	return u.Algorithm.ValueString()
+}
+
+// fileUsers returns the user blocks of set sorted by username.
+func fileUsers(ctx context.Context, set types.Set) ([]FileUserModel, diag.Diagnostics) {
+	var users []FileUserModel
+	diags := set.ElementsAs(ctx, &users, false)
+	slices.SortFunc(users, func(a, b FileUserModel) int {
+		return strings.Compare(a.Username.ValueString(), b.Username.ValueString())
+	})
+	return users, diags
+}
+
+// reuseEntries returns an entry for every user. Entries use the configured
+// hash, or the hash of the same user in existing when it still verifies
+// against the password with the configured algorithm. The hash is left empty
+// otherwise and complete is false. htdigest entries for a non-empty realm are
+// deterministic and always complete.
+func reuseEntries(users []FileUserModel, existing []htpasswdEntry, realm string) ([]htpasswdEntry, bool) {
+	hashes := make(map[string]string, len(existing))
+	for _, entry := range existing {
+		hashes[entry.Username] = entry.Hash
+	}
+
+	complete := true
+	entries := make([]htpasswdEntry, len(users))
+	for i, user := range users {
+		entries[i].Username = user.Username.ValueString()
+		if realm != "" {
+			digest := user.Hash.ValueString()
+			if user.Hash.IsNull() {
+				digest = htdigestDigest(entries[i].Username, realm, user.Password.ValueString())
+			}
+			entries[i].Hash = realm + ":" + digest
+			continue
+		}
+		if !user.Hash.IsNull() {
+			entries[i].Hash = user.Hash.ValueString()
+			continue
+		}
+		hash, ok := hashes[entries[i].Username]
+		hasher, detected := detectHasher(hash)
+		if ok && detected && hasher.Name() == user.algorithm() && hasher.Verify(user.Password.ValueString(), hash, HashOptions{}) {
+			entries[i].Hash = hash
+			continue
+		}
+		complete = false
+	}
+	return entries, complete
+}
+
+// renderHtpasswd renders entries as an htpasswd file with one user:hash line
+// per entry.
+func renderHtpasswd(entries []htpasswdEntry) string {
+	var sb strings.Builder
+	for _, entry := range entries {
+		sb.WriteString(entry.Username + ":" + entry.Hash + "\n")
+	}
+	return sb.String()
+}
+
+// parseHtpasswd parses the user:hash lines of an htpasswd file. Blank lines,
+// comments and lines without a colon are skipped.
+func parseHtpasswd(content string) []htpasswdEntry {
+	var entries []htpasswdEntry
+	for _, line := range strings.Split(content, "\n") {
+		line = strings.TrimRight(line, "\r")
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		username, hash, ok := strings.Cut(line, ":")
+		if !ok {
+			continue
+		}
+		entries = append(entries, htpasswdEntry{Username: username, Hash: hash})
+	}
+	return entries
+}
+
+// fileAlgorithms returns the algorithms that can be used in htpasswd files,
+// which are all algorithms producing a hash with a detectable format.
+func fileAlgorithms() []string {
+	var names []string
+	for _, h := range hashers {
+		if slices.ContainsFunc(hashPrefixes, func(p hashPrefix) bool { return p.hasher.Name() == h.Name() }) {
+			names = append(names, h.Name())
+		}
+	}
+	return names
+}
+
+// validateUsername validates a username of an htpasswd file entry.
+func validateUsername(username string) error {
+	if username == "" {
+		return fmt.Errorf("username must not be empty")
+	}
+	if strings.ContainsAny(username, ":\r\n") || strings.HasPrefix(username, "#") {
+		return fmt.Errorf("username %q must not contain colons or line breaks or start with #", username)
+	}
+	return nil
+}
+
+// validateRealm validates the realm of htdigest entries.
+func validateRealm(realm string) error {
+	if realm == "" || strings.ContainsAny(realm, ":\r\n") {
+		return fmt.Errorf("realm must not be empty or contain colons or line breaks, got %q", realm)
+	}
+	return nil
+}
+
+// parseFilePermission parses an octal file mode such as 0640.
+func parseFilePermission(permission string) (os.FileMode, error) {
+	mode, err := strconv.ParseUint(permission, 8, 32)
+	if err != nil || mode > 0o777 {
+		return 0, fmt.Errorf("file permission must be an octal mode between 0000 and 0777, got %q", permission)
+	}
+	return os.FileMode(mode), nil
+}
@@ -0,0 +1,298 @@
+package htpasswd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/terraform"
+)
+
+func TestAccResourceFile_Basic(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "conf", ".htpasswd")
+	config := testAccResourceFileConfig(filename, "0640")
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: config,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("htpasswd_file.test", "id", filename),
+					resource.TestCheckResourceAttr("htpasswd_file.test", "file_permission", "0640"),
+					resource.TestMatchResourceAttr("htpasswd_file.test", "content", regexp.MustCompile(
+						`^alice:\$apr1\$[^\n]+\nbob:\$2a\$04\$CCCCCCCCCCCCCCCCCCCCC\.E5YPO9kmyuRGyh0XouQYb4YMJKvyOeW\ncarol:\$2a\$10\$[^\n]+\n$`)),
+					testAccCheckFileEntries(filename, map[string]string{"alice": "secret123", "carol": "hunter2"}),
+					testAccCheckFileMode(filename, 0o640),
+				),
+			},
+			{
+				// Hashes with a random salt must not cause a difference.
+				Config:   config,
+				PlanOnly: true,
+			},
+			{
+				Config: testAccResourceFileConfig(filename, "0600"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("htpasswd_file.test", "file_permission", "0600"),
+					testAccCheckFileMode(filename, 0o600),
+				),
+			},
+		},
+	})
+}
+
+func TestAccResourceFile_OutOfBandEdits(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), ".htpasswd")
+	config := testAccResourceFileConfig(filename, "0600")
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: config,
+			},
+			{
+				PreConfig: func() {
+					f, err := os.OpenFile(filename, os.O_APPEND|os.O_WRONLY, 0)
+					if err != nil {
+						t.Fatal(err)
+					}
+					defer f.Close()
+					if _, err := f.WriteString("mallory:{SHA}W6ph5Mm5Pz8GgiULbPgzG37mj9g=\n"); err != nil {
+						t.Fatal(err)
+					}
+				},
+				Config:             config,
+				PlanOnly:           true,
+				ExpectNonEmptyPlan: true,
+			},
+			{
+				Config: config,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestMatchResourceAttr("htpasswd_file.test", "content", regexp.MustCompile(`^alice:.*\nbob:.*\ncarol:.*\n$`)),
+					testAccCheckFileEntries(filename, map[string]string{"alice": "secret123", "carol": "hunter2"}),
+				),
+			},
+			{
+				PreConfig: func() {
+					if err := os.Remove(filename); err != nil {
+						t.Fatal(err)
+					}
+				},
+				Config: config,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckFileEntries(filename, map[string]string{"alice": "secret123", "carol": "hunter2"}),
+				),
+			},
+		},
+	})
+}
+
+func TestAccResourceFile_Htdigest(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), ".htdigest")
+	config := fmt.Sprintf(`
+resource "htpasswd_file" "test" {
+	path   = %q
+	format = "htdigest"
+	realm  = "private"
+
+	user {
+		username = "alice"
+		password = "secret123"
+	}
+
+	user {
+		username = "bob"
+		hash     = "0123456789abcdef0123456789abcdef"
+	}
+}
+`, filename)
+	expected := "alice:private:4577980e2c512b92b0c67633674f8f07\nbob:private:0123456789abcdef0123456789abcdef\n"
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: config,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("htpasswd_file.test", "content", expected),
+					func(_ *terraform.State) error {
+						content, err := os.ReadFile(filename)
+						if err != nil {
+							return err
+						}
+						if string(content) != expected {
+							return fmt.Errorf("%s contains %q, expected %q", filename, content, expected)
+						}
+						return nil
+					},
+				),
+			},
+			{
+				Config:   config,
+				PlanOnly: true,
+			},
+		},
+	})
+}
+
+func TestAccResourceFile_SaltedHashes(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), ".htpasswd")
+	config := fmt.Sprintf(`
+resource "htpasswd_file" "test" {
+	path = %q
+
+	user {
+		username  = "alice"
+		password  = "secret123"
+		algorithm = "sha512"
+	}
+
+	user {
+		username  = "bob"
+		password  = "hunter2"
+		algorithm = "sha256_crypt"
+	}
+}
+`, filename)
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: config,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestMatchResourceAttr("htpasswd_file.test", "content", regexp.MustCompile(
+						`^alice:\$6\$[./0-9A-Za-z]{8}\$[^\n]+\nbob:\$5\$[./0-9A-Za-z]{8}\$[^\n]+\n$`)),
+					testAccCheckFileEntries(filename, map[string]string{"alice": "secret123", "bob": "hunter2"}),
+				),
+			},
+			{
+				Config:   config,
+				PlanOnly: true,
+			},
+		},
+	})
+}
+
+func TestAccResourceFile_InvalidUser(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(`
+resource "htpasswd_file" "test" {
+	path = %q
+
+	user {
+		username = "alice"
+		password = "secret123"
+		hash     = "{SHA}W6ph5Mm5Pz8GgiULbPgzG37mj9g="
+	}
+}
+`, filepath.Join(t.TempDir(), ".htpasswd")),
+				ExpectError: regexp.MustCompile(`must set exactly one of password or hash`),
+			},
+			{
+				Config: fmt.Sprintf(`
+resource "htpasswd_file" "test" {
+	path = %q
+
+	user {
+		username  = "alice"
+		password  = "secret123"
+		algorithm = "sha256"
+	}
+}
+`, filepath.Join(t.TempDir(), ".htpasswd")),
+				ExpectError: regexp.MustCompile(`Invalid Algorithm`),
+			},
+			{
+				Config: fmt.Sprintf(`
+resource "htpasswd_file" "test" {
+	path   = %q
+	format = "htdigest"
+}
+`, filepath.Join(t.TempDir(), ".htdigest")),
+				ExpectError: regexp.MustCompile(`realm is required when format is htdigest`),
+			},
+		},
+	})
+}
+
+func testAccResourceFileConfig(filename, permission string) string {
+	return fmt.Sprintf(`
+resource "htpasswd_file" "test" {
+	path            = %q
+	file_permission = %q
+
+	user {
+		username = "carol"
+		password = "hunter2"
+	}
+
+	user {
+		username  = "alice"
+		password  = "secret123"
+		algorithm = "apr1"
+	}
+
+	user {
+		username = "bob"
+		hash     = "$2a$04$CCCCCCCCCCCCCCCCCCCCC.E5YPO9kmyuRGyh0XouQYb4YMJKvyOeW"
+	}
+}
+`, filename, permission)
+}
+
+// testAccCheckFileEntries checks that the file on disk contains an entry
+// matching each username and password in passwords.
+func testAccCheckFileEntries(filename string, passwords map[string]string) resource.TestCheckFunc {
+	return func(_ *terraform.State) error {
+		content, err := os.ReadFile(filename)
+		if err != nil {
+			return err
+		}
+		hashes := make(map[string]string)
+		for _, entry := range parseHtpasswd(string(content)) {
+			hashes[entry.Username] = entry.Hash
+		}
+		for username, password := range passwords {
+			hasher, ok := detectHasher(hashes[username])
+			if !ok || !hasher.Verify(password, hashes[username], HashOptions{}) {
+				return fmt.Errorf("entry of user %q in %s does not verify: %q", username, filename, hashes[username])
+			}
+		}
+		return nil
+	}
+}
+
+func testAccCheckFileMode(filename string, mode os.FileMode) resource.TestCheckFunc {
+	return func(_ *terraform.State) error {
+		info, err := os.Stat(filename)
+		if err != nil {
+			return err
+		}
+		if info.Mode().Perm() != mode {
+			return fmt.Errorf("%s has mode %04o, expected %04o", filename, info.Mode().Perm(), mode)
+		}
+		return nil
+	}
+}
+
+func TestRenderUsers_UnknownAlgorithm(t *testing.T) {
+	users := []FileUserModel{{
+		Username:  types.StringValue("alice"),
+		Password:  types.StringValue("secret123"),
+		Hash:      types.StringNull(),
+		Algorithm: types.StringValue("md5_crypt"),
+	}}
+	if _, diags := renderUsers(users, nil, ""); !diags.HasError() {
+		t.Error("renderUsers() with an unknown algorithm returned no error")
+	}
+}
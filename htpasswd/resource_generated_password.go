@@ -0,0 +1,692 @@
+package htpasswd
+
+import (
+	"context"
+	"crypto/rand"
+	"fmt"
+	"math/big"
+	"slices"
+	"strings"
+
+	"github.com/hashicorp/go-uuid"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/booldefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/boolplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64default"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/listplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/mapplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringdefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+var _ resource.Resource = &GeneratedPasswordResource{}
+var _ resource.ResourceWithConfigure = &GeneratedPasswordResource{}
+var _ resource.ResourceWithValidateConfig = &GeneratedPasswordResource{}
+
+type GeneratedPasswordResource struct {
+	config *providerConfig
+}
+
+type GeneratedPasswordModel struct {
+	ID                types.String `tfsdk:"id"`
+	Length            types.Int64  `tfsdk:"length"`
+	Upper             types.Bool   `tfsdk:"upper"`
+	Lower             types.Bool   `tfsdk:"lower"`
+	Numeric           types.Bool   `tfsdk:"numeric"`
+	Special           types.Bool   `tfsdk:"special"`
+	ExcludeCharacters types.String `tfsdk:"exclude_characters"`
+	Wordlist          types.List   `tfsdk:"wordlist"`
+	Words             types.Int64  `tfsdk:"words"`
+	Separator         types.String `tfsdk:"separator"`
+	Keepers           types.Map    `tfsdk:"keepers"`
+	Salt              types.String `tfsdk:"salt"`
+	Sha256Rounds      types.Int64  `tfsdk:"sha256_rounds"`
+	Sha512Rounds      types.Int64  `tfsdk:"sha512_rounds"`
+	BcryptCost        types.Int64  `tfsdk:"bcrypt_cost"`
+	BcryptVariant     types.String `tfsdk:"bcrypt_variant"`
+	Argon2Memory      types.Int64  `tfsdk:"argon2_memory"`
+	Argon2Time        types.Int64  `tfsdk:"argon2_time"`
+	Argon2Parallelism types.Int64  `tfsdk:"argon2_parallelism"`
+	ScryptN           types.Int64  `tfsdk:"scrypt_n"`
+	ScryptR           types.Int64  `tfsdk:"scrypt_r"`
+	ScryptP           types.Int64  `tfsdk:"scrypt_p"`
+	ScryptFormat      types.String `tfsdk:"scrypt_format"`
+	Pbkdf2Iterations  types.Int64  `tfsdk:"pbkdf2_iterations"`
+	Username          types.String `tfsdk:"username"`
+	Realm             types.String `tfsdk:"realm"`
+	Algorithms        types.Set    `tfsdk:"algorithms"`
+	Result            types.String `tfsdk:"result"`
+	Argon2id          types.String `tfsdk:"argon2id"`
+	Apr1              types.String `tfsdk:"apr1"`
+	Bcrypt            types.String `tfsdk:"bcrypt"`
+	Htdigest          types.String `tfsdk:"htdigest"`
+	Pbkdf2Sha512      types.String `tfsdk:"pbkdf2_sha512"`
+	Scrypt            types.String `tfsdk:"scrypt"`
+	Sha1              types.String `tfsdk:"sha1"`
+	Sha256            types.String `tfsdk:"sha256"`
+	Sha256Crypt       types.String `tfsdk:"sha256_crypt"`
+	Sha512            types.String `tfsdk:"sha512"`
+	Yescrypt          types.String `tfsdk:"yescrypt"`
+}
+
+// hashOptions returns the hash settings configured on the resource.
+func (m *GeneratedPasswordModel) hashOptions() HashOptions {
+	return HashOptions{
+		Salt:              m.Salt.ValueString(),
+		Sha256Rounds:      m.Sha256Rounds.ValueInt64(),
+		Sha512Rounds:      m.Sha512Rounds.ValueInt64(),
+		BcryptCost:        m.BcryptCost.ValueInt64(),
+		BcryptVariant:     m.BcryptVariant.ValueString(),
+		Argon2Memory:      m.Argon2Memory.ValueInt64(),
+		Argon2Time:        m.Argon2Time.ValueInt64(),
+		Argon2Parallelism: m.Argon2Parallelism.ValueInt64(),
+		ScryptN:           m.ScryptN.ValueInt64(),
+		ScryptR:           m.ScryptR.ValueInt64(),
+		ScryptP:           m.ScryptP.ValueInt64(),
+		ScryptFormat:      m.ScryptFormat.ValueString(),
+		Pbkdf2Iterations:  m.Pbkdf2Iterations.ValueInt64(),
+		Username:          m.Username.ValueString(),
+		Realm:             m.Realm.ValueString(),
+		Algorithms:        algorithmsValue(m.Algorithms),
+	}
+}
+
+// hashValues maps the Hasher names to the hash attributes of the model.
+func (m *GeneratedPasswordModel) hashValues() map[string]*types.String {
+	return map[string]*types.String{
+		"apr1":          &m.Apr1,
+		"argon2id":      &m.Argon2id,
+		"bcrypt":        &m.Bcrypt,
+		"htdigest":      &m.Htdigest,
+		"pbkdf2_sha512": &m.Pbkdf2Sha512,
+		"scrypt":        &m.Scrypt,
+		"sha1":          &m.Sha1,
+		"sha256":        &m.Sha256,
+		"sha256_crypt":  &m.Sha256Crypt,
+		"sha512":        &m.Sha512,
+		"yescrypt":      &m.Yescrypt,
+	}
+}
+
+// characterSets returns the enabled character classes without the excluded
+// characters. Classes left empty by the exclusion are omitted. Unset classes
+// are enabled, as in the schema defaults.
+func (m *GeneratedPasswordModel) characterSets() []string {
+	classes := []struct {
+		enabled types.Bool
+		chars   string
+	}{
+		{m.Lower, generatedPasswordLower},
+		{m.Upper, generatedPasswordUpper},
+		{m.Numeric, generatedPasswordNumeric},
+		{m.Special, generatedPasswordSpecial},
+	}
+
+	var sets []string
+	for _, class := range classes {
+		if !class.enabled.IsNull() && !class.enabled.ValueBool() {
+			continue
+		}
+		set := strings.Map(func(r rune) rune {
+			if strings.ContainsRune(m.ExcludeCharacters.ValueString(), r) {
+				return -1
+			}
+			return r
+		}, class.chars)
+		if set != "" {
+			sets = append(sets, set)
+		}
+	}
+	return sets
+}
+
+// Character classes used for generated passwords.
+const (
+	generatedPasswordLower   = "abcdefghijklmnopqrstuvwxyz"
+	generatedPasswordUpper   = "ABCDEFGHIJKLMNOPQRSTUVWXYZ"
+	generatedPasswordNumeric = "0123456789"
+	generatedPasswordSpecial = "!@#%&*()-_=+[]{}<>:?"
+)
+
+const (
+	generatedPasswordDefaultLength = 32
+	generatedPasswordDefaultWords  = 6
+	// generatedPasswordDefaultSeparator joins the words of a passphrase.
+	generatedPasswordDefaultSeparator = "-"
+	// generatedPasswordMaxLength is the longest password bcrypt accepts.
+	generatedPasswordMaxLength = 72
+)
+
+func NewGeneratedPasswordResource() resource.Resource {
+	return &GeneratedPasswordResource{}
+}
+
+func (r *GeneratedPasswordResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_generated_password"
+}
+
+func (r *GeneratedPasswordResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Generates a random password and its htpasswd compatible hashes",
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Computed:    true,
+				Description: "Resource identifier",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"length": schema.Int64Attribute{
+				Optional:    true,
+				Computed:    true,
+				Default:     int64default.StaticInt64(generatedPasswordDefaultLength),
+				Description: fmt.Sprintf("Number of characters of the password (1-%d). Defaults to %d. Not used with wordlist.", generatedPasswordMaxLength, generatedPasswordDefaultLength),
+				PlanModifiers: []planmodifier.Int64{
+					int64planmodifier.RequiresReplace(),
+				},
+			},
+			"upper": schema.BoolAttribute{
+				Optional:    true,
+				Computed:    true,
+				Default:     booldefault.StaticBool(true),
+				Description: "Include upper case letters. Defaults to true.",
+				PlanModifiers: []planmodifier.Bool{
+					boolplanmodifier.RequiresReplace(),
+				},
+			},
+			"lower": schema.BoolAttribute{
+				Optional:    true,
+				Computed:    true,
+				Default:     booldefault.StaticBool(true),
+				Description: "Include lower case letters. Defaults to true.",
+				PlanModifiers: []planmodifier.Bool{
+					boolplanmodifier.RequiresReplace(),
+				},
+			},
+			"numeric": schema.BoolAttribute{
+				Optional:    true,
+				Computed:    true,
+				Default:     booldefault.StaticBool(true),
+				Description: "Include digits. Defaults to true.",
+				PlanModifiers: []planmodifier.Bool{
+					boolplanmodifier.RequiresReplace(),
+				},
+			},
+			"special": schema.BoolAttribute{
+				Optional:    true,
+				Computed:    true,
+				Default:     booldefault.StaticBool(true),
+				Description: "Include the special characters " + generatedPasswordSpecial + ". Defaults to true.",
+				PlanModifiers: []planmodifier.Bool{
+					boolplanmodifier.RequiresReplace(),
+				},
+			},
+			"exclude_characters": schema.StringAttribute{
+				Optional:    true,
+				Description: "Characters never used in the password, e.g. look-alikes such as 0O1lI",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"wordlist": schema.ListAttribute{
+				Optional:    true,
+				ElementType: types.StringType,
+				Description: "Words to build a passphrase from. When set, the password is made of the given number of words randomly chosen from the list, joined by separator, and the character settings can not be used.",
+				PlanModifiers: []planmodifier.List{
+					listplanmodifier.RequiresReplace(),
+				},
+			},
+			"words": schema.Int64Attribute{
+				Optional:    true,
+				Computed:    true,
+				Default:     int64default.StaticInt64(generatedPasswordDefaultWords),
+				Description: fmt.Sprintf("Number of words of a passphrase. Defaults to %d. Requires wordlist.", generatedPasswordDefaultWords),
+				PlanModifiers: []planmodifier.Int64{
+					int64planmodifier.RequiresReplace(),
+				},
+			},
+			"separator": schema.StringAttribute{
+				Optional:    true,
+				Computed:    true,
+				Default:     stringdefault.StaticString(generatedPasswordDefaultSeparator),
+				Description: "Separator between the words of a passphrase. Defaults to -. Requires wordlist.",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"keepers": schema.MapAttribute{
+				Optional:    true,
+				ElementType: types.StringType,
+				Description: "Arbitrary map of values that, when changed, forces a new password to be generated",
+				PlanModifiers: []planmodifier.Map{
+					mapplanmodifier.RequiresReplace(),
+				},
+			},
+			"salt": schema.StringAttribute{
+				Optional:    true,
+				Computed:    true,
+				Description: "Salt for apr1, sha256_crypt, sha512, argon2id, pbkdf2_sha512, scrypt and yescrypt hashes. Must be exactly 8 characters from the crypt base64 alphabet. When omitted, a random salt is generated and kept in state. The pbkdf2_sha512 hash uses the first 16 bytes of the SHA-256 digest of the salt, as SP 800-132 requires salts of at least 128 bits.",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"sha256_rounds": schema.Int64Attribute{
+				Optional:    true,
+				Description: "Number of rounds for the sha256_crypt hash (1000-999999999). When set, the hash includes a rounds=N$ segment.",
+			},
+			"sha512_rounds": schema.Int64Attribute{
+				Optional:    true,
+				Description: "Number of rounds for the sha512 hash (1000-999999999). When set, the hash includes a rounds=N$ segment.",
+			},
+			"bcrypt_cost": schema.Int64Attribute{
+				Optional:    true,
+				Description: "Cost factor for the bcrypt hash (4-31). Defaults to 10.",
+			},
+			"bcrypt_variant": schema.StringAttribute{
+				Optional:    true,
+				Description: "Prefix variant for the bcrypt hash: 2a, 2b or 2y. Defaults to 2a.",
+			},
+			"argon2_memory": schema.Int64Attribute{
+				Optional:    true,
+				Description: "Memory in KiB used for the argon2id hash. Defaults to 65536.",
+			},
+			"argon2_time": schema.Int64Attribute{
+				Optional:    true,
+				Description: "Number of iterations used for the argon2id hash. Defaults to 3.",
+			},
+			"argon2_parallelism": schema.Int64Attribute{
+				Optional:    true,
+				Description: "Degree of parallelism used for the argon2id hash (1-255). Defaults to 4.",
+			},
+			"scrypt_n": schema.Int64Attribute{
+				Optional:    true,
+				Description: "CPU/memory cost parameter N for the scrypt hash. Must be a power of 2. Defaults to 32768.",
+			},
+			"scrypt_r": schema.Int64Attribute{
+				Optional:    true,
+				Description: "Block size parameter r for the scrypt hash. Defaults to 8.",
+			},
+			"scrypt_p": schema.Int64Attribute{
+				Optional:    true,
+				Description: "Parallelization parameter p for the scrypt hash. Defaults to 1.",
+			},
+			"scrypt_format": schema.StringAttribute{
+				Optional:    true,
+				Description: "Output format of the scrypt hash: phc ($scrypt$ln=...) or crypt ($7$). Defaults to phc.",
+			},
+			"pbkdf2_iterations": schema.Int64Attribute{
+				Optional:    true,
+				Description: "Number of iterations for the pbkdf2_sha512 hash (1000-2147483647). Defaults to 210000.",
+			},
+			"username": schema.StringAttribute{
+				Optional:    true,
+				Description: "Username for the htdigest hash. Requires realm.",
+			},
+			"realm": schema.StringAttribute{
+				Optional:    true,
+				Description: "Realm for the htdigest hash. Requires username.",
+			},
+			"algorithms": schema.SetAttribute{
+				Optional:    true,
+				ElementType: types.StringType,
+				Description: "Hashes to compute: " + strings.Join(hasherNames(), ", ") + ". The attributes of other hashes are null. Defaults to the provider algorithms, or all.",
+			},
+			"result": schema.StringAttribute{
+				Computed:    true,
+				Sensitive:   true,
+				Description: "The generated password",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"argon2id": schema.StringAttribute{
+				Computed:    true,
+				Description: "Argon2id hash of the password in PHC string format",
+			},
+			"apr1": schema.StringAttribute{
+				Computed:    true,
+				Description: "APR1-MD5 hash of the password",
+			},
+			"bcrypt": schema.StringAttribute{
+				Computed:    true,
+				Description: "Bcrypt hash of the password",
+			},
+			"htdigest": schema.StringAttribute{
+				Computed:    true,
+				Description: "htdigest entry user:realm:MD5(user:realm:password), null unless username and realm are set",
+			},
+			"pbkdf2_sha512": schema.StringAttribute{
+				Computed:    true,
+				Description: "PBKDF2-HMAC-SHA512 hash of the password in passlib $pbkdf2-sha512$ format",
+			},
+			"scrypt": schema.StringAttribute{
+				Computed:    true,
+				Description: "Scrypt hash of the password",
+			},
+			"sha1": schema.StringAttribute{
+				Computed:    true,
+				Description: "SHA1 crypt hash of the password (insecure)",
+			},
+			"sha256": schema.StringAttribute{
+				Computed:    true,
+				Description: "SHA-256 hash of the password (hex encoded)",
+			},
+			"sha256_crypt": schema.StringAttribute{
+				Computed:    true,
+				Description: "SHA-256 crypt hash of the password",
+			},
+			"sha512": schema.StringAttribute{
+				Computed:    true,
+				Description: "SHA-512 crypt hash of the password",
+			},
+			"yescrypt": schema.StringAttribute{
+				Computed:    true,
+				Description: "Yescrypt hash of the password",
+			},
+		},
+	}
+}
+
+func (r *GeneratedPasswordResource) Configure(_ context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+	config, ok := req.ProviderData.(*providerConfig)
+	if !ok {
+		resp.Diagnostics.AddError("Unexpected Provider Data", fmt.Sprintf("Expected *providerConfig, got %T", req.ProviderData))
+		return
+	}
+	r.config = config
+}
+
+func (r *GeneratedPasswordResource) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var data GeneratedPasswordModel
+
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(validateConfigHashOptions(data.hashOptions(), map[string]attr.Value{
+		"username":           data.Username,
+		"realm":              data.Realm,
+		"argon2_parallelism": data.Argon2Parallelism,
+	})...)
+
+	if data.Wordlist.IsUnknown() {
+		return
+	}
+
+	if !data.Wordlist.IsNull() {
+		for _, setting := range []struct {
+			name  string
+			value attr.Value
+		}{
+			{"length", data.Length},
+			{"upper", data.Upper},
+			{"lower", data.Lower},
+			{"numeric", data.Numeric},
+			{"special", data.Special},
+			{"exclude_characters", data.ExcludeCharacters},
+		} {
+			if !setting.value.IsNull() {
+				resp.Diagnostics.AddAttributeError(path.Root(setting.name), "Invalid Configuration", fmt.Sprintf("%s can not be combined with wordlist", setting.name))
+			}
+		}
+
+		var wordlist []string
+		resp.Diagnostics.Append(data.Wordlist.ElementsAs(ctx, &wordlist, true)...)
+		if resp.Diagnostics.HasError() || data.Words.IsUnknown() || data.Separator.IsUnknown() {
+			return
+		}
+		if err := validateWordlist(wordlist, data.words(), data.separator()); err != nil {
+			resp.Diagnostics.AddAttributeError(path.Root("wordlist"), "Invalid Wordlist", err.Error())
+		}
+		return
+	}
+
+	if !data.Words.IsNull() {
+		resp.Diagnostics.AddAttributeError(path.Root("words"), "Invalid Configuration", "words requires wordlist")
+	}
+	if !data.Separator.IsNull() {
+		resp.Diagnostics.AddAttributeError(path.Root("separator"), "Invalid Configuration", "separator requires wordlist")
+	}
+
+	if !data.Length.IsNull() && !data.Length.IsUnknown() {
+		if length := data.Length.ValueInt64(); length < 1 || length > generatedPasswordMaxLength {
+			resp.Diagnostics.AddAttributeError(path.Root("length"), "Invalid Length", fmt.Sprintf("length must be between 1 and %d, got %d", generatedPasswordMaxLength, length))
+		}
+	}
+
+	if data.Upper.IsUnknown() || data.Lower.IsUnknown() || data.Numeric.IsUnknown() || data.Special.IsUnknown() || data.ExcludeCharacters.IsUnknown() {
+		return
+	}
+	if len(data.characterSets()) == 0 {
+		resp.Diagnostics.AddError("Invalid Configuration", "At least one character class must be enabled with characters that are not excluded")
+	}
+}
+
+// words returns the configured number of passphrase words, or the default.
+func (m *GeneratedPasswordModel) words() int64 {
+	if m.Words.IsNull() {
+		return generatedPasswordDefaultWords
+	}
+	return m.Words.ValueInt64()
+}
+
+// separator returns the configured passphrase separator, or the default.
+func (m *GeneratedPasswordModel) separator() string {
+	if m.Separator.IsNull() {
+		return generatedPasswordDefaultSeparator
+	}
+	return m.Separator.ValueString()
+}
+
+func (r *GeneratedPasswordResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var data GeneratedPasswordModel
+
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	var password string
+	var err error
+	if data.Wordlist.IsNull() {
+		password, err = generatePassword(int(data.Length.ValueInt64()), data.characterSets())
+	} else {
+		var wordlist []string
+		resp.Diagnostics.Append(data.Wordlist.ElementsAs(ctx, &wordlist, false)...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+		password, err = generatePassphrase(wordlist, int(data.Words.ValueInt64()), data.Separator.ValueString())
+	}
+	if err != nil {
+		resp.Diagnostics.AddError("Password Error", fmt.Sprintf("Failed to generate password: %s", err))
+		return
+	}
+	data.Result = types.StringValue(password)
+
+	if data.Salt.IsUnknown() {
+		salt, err := randomSalt(generatedSaltLength)
+		if err != nil {
+			resp.Diagnostics.AddError("Salt Error", fmt.Sprintf("Failed to generate salt: %s", err))
+			return
+		}
+		data.Salt = types.StringValue(salt)
+	}
+
+	opts := r.config.hashOptions(data.hashOptions())
+	resp.Diagnostics.Append(validateHashOptions(opts)...)
+	resp.Diagnostics.Append(r.config.validate(opts)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(generateHashes(password, opts, data.hashValues())...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	id, err := uuid.GenerateUUID()
+	if err != nil {
+		resp.Diagnostics.AddError("ID Error", fmt.Sprintf("Failed to generate resource identifier: %s", err))
+		return
+	}
+	data.ID = types.StringValue(id)
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *GeneratedPasswordResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var data GeneratedPasswordModel
+
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(refreshHashes(data.Result.ValueString(), r.config.hashOptions(data.hashOptions()), data.hashValues())...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+// Update only runs for changes of the hash settings, which keep the password.
+// Hashes whose settings changed are regenerated, the others are kept as long
+// as they still verify against the password.
+func (r *GeneratedPasswordResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var data, state GeneratedPasswordModel
+
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	opts := r.config.hashOptions(data.hashOptions())
+	resp.Diagnostics.Append(validateHashOptions(opts)...)
+	resp.Diagnostics.Append(r.config.validate(opts)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	changed := opts.changed(r.config.hashOptions(state.hashOptions()))
+	prior := state.hashValues()
+	for name, value := range data.hashValues() {
+		*value = *prior[name]
+		if slices.Contains(changed, name) {
+			*value = types.StringNull()
+		}
+	}
+	resp.Diagnostics.Append(refreshHashes(data.Result.ValueString(), opts, data.hashValues())...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *GeneratedPasswordResource) Delete(_ context.Context, _ resource.DeleteRequest, _ *resource.DeleteResponse) {
+}
+
+// validateWordlist validates the passphrase settings. The longest possible
+// passphrase must fit in the 72 bytes bcrypt accepts.
+func validateWordlist(wordlist []string, words int64, separator string) error {
+	if len(wordlist) < 2 {
+		return fmt.Errorf("wordlist must contain at least 2 words, got %d", len(wordlist))
+	}
+	longest := 0
+	for _, word := range wordlist {
+		if word == "" {
+			return fmt.Errorf("wordlist must not contain empty words")
+		}
+		longest = max(longest, len(word))
+	}
+	if words < 1 {
+		return fmt.Errorf("words must be at least 1, got %d", words)
+	}
+	if length := int(words)*longest + int(words-1)*len(separator); length > generatedPasswordMaxLength {
+		return fmt.Errorf("a passphrase of %d words can be up to %d bytes long, but bcrypt only accepts %d", words, length, generatedPasswordMaxLength)
+	}
+	return nil
+}
+
+// generatePassword generates a random password of length characters from the
+// union of sets. When length allows, every set is used at least once.
+func generatePassword(length int, sets []string) (string, error) {
+	if len(sets) == 0 {
+		return "", fmt.Errorf("no characters to choose from")
+	}
+
+	password := make([]byte, 0, length)
+	if length >= len(sets) {
+		for _, set := range sets {
+			c, err := randomChoice(set)
+			if err != nil {
+				return "", err
+			}
+			password = append(password, c)
+		}
+	}
+
+	all := strings.Join(sets, "")
+	for len(password) < length {
+		c, err := randomChoice(all)
+		if err != nil {
+			return "", err
+		}
+		password = append(password, c)
+	}
+
+	// Shuffle so that the guaranteed characters are not always first.
+	for i := len(password) - 1; i > 0; i-- {
+		j, err := randomIndex(i + 1)
+		if err != nil {
+			return "", err
+		}
+		password[i], password[j] = password[j], password[i]
+	}
+	return string(password), nil
+}
+
+// generatePassphrase joins the given number of randomly chosen words of
+// wordlist with separator.
+func generatePassphrase(wordlist []string, words int, separator string) (string, error) {
+	chosen := make([]string, words)
+	for i := range chosen {
+		j, err := randomIndex(len(wordlist))
+		if err != nil {
+			return "", err
+		}
+		chosen[i] = wordlist[j]
+	}
+	return strings.Join(chosen, separator), nil
+}
+
+// randomChoice returns a uniformly chosen random character of set.
+func randomChoice(set string) (byte, error) {
+	i, err := randomIndex(len(set))
+	if err != nil {
+		return 0, err
+	}
+	return set[i], nil
+}
+
+// randomIndex returns a uniformly chosen random number in [0, n).
+func randomIndex(n int) (int, error) {
+	i, err := rand.Int(rand.Reader, big.NewInt(int64(n)))
+	if err != nil {
+		return 0, err
+	}
+	return int(i.Int64()), nil
+}
@@ -0,0 +1,241 @@
+package htpasswd
+
+import (
+	"fmt"
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/plancheck"
+	"github.com/hashicorp/terraform-plugin-testing/terraform"
+)
+
+func TestAccResourceGeneratedPassword_Basic(t *testing.T) {
+	config := `
+resource "htpasswd_generated_password" "test" {
+	length   = 20
+	special  = false
+	username = "alice"
+	realm    = "private"
+}
+`
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: config,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestMatchResourceAttr("htpasswd_generated_password.test", "result", regexp.MustCompile(`^[A-Za-z0-9]{20}$`)),
+					resource.TestMatchResourceAttr("htpasswd_generated_password.test", "salt", regexp.MustCompile(`^[./0-9A-Za-z]{8}$`)),
+					testAccCheckGeneratedPasswordHashes("htpasswd_generated_password.test"),
+				),
+			},
+			{
+				Config:   config,
+				PlanOnly: true,
+			},
+		},
+	})
+}
+
+func TestAccResourceGeneratedPassword_CharacterClasses(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: `
+resource "htpasswd_generated_password" "test" {
+	length             = 12
+	upper              = false
+	lower              = false
+	special            = false
+	exclude_characters = "0123456"
+}
+`,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestMatchResourceAttr("htpasswd_generated_password.test", "result", regexp.MustCompile(`^[789]{12}$`)),
+					testAccCheckGeneratedPasswordHashes("htpasswd_generated_password.test"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccResourceGeneratedPassword_Passphrase(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: `
+resource "htpasswd_generated_password" "test" {
+	wordlist  = ["correct", "horse", "battery", "staple"]
+	words     = 4
+	separator = " "
+}
+`,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestMatchResourceAttr("htpasswd_generated_password.test", "result", regexp.MustCompile(`^(correct|horse|battery|staple)( (correct|horse|battery|staple)){3}$`)),
+					testAccCheckGeneratedPasswordHashes("htpasswd_generated_password.test"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccResourceGeneratedPassword_UpdateHashSettings(t *testing.T) {
+	kept := make(map[string]string)
+	update := resource.ConfigPlanChecks{
+		PreApply: []plancheck.PlanCheck{
+			plancheck.ExpectResourceAction("htpasswd_generated_password.test", plancheck.ResourceActionUpdate),
+		},
+	}
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: `
+resource "htpasswd_generated_password" "test" {
+	salt        = "saltySal"
+	bcrypt_cost = 4
+}
+`,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestMatchResourceAttr("htpasswd_generated_password.test", "bcrypt", regexp.MustCompile(`^\$2a\$04\$`)),
+					testAccCheckGeneratedPasswordKept(kept, "result", "sha512"),
+				),
+			},
+			{
+				Config: `
+resource "htpasswd_generated_password" "test" {
+	salt        = "saltySal"
+	bcrypt_cost = 5
+	username    = "alice"
+	realm       = "private"
+	algorithms  = ["bcrypt", "htdigest", "sha512"]
+}
+`,
+				ConfigPlanChecks: update,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestMatchResourceAttr("htpasswd_generated_password.test", "bcrypt", regexp.MustCompile(`^\$2a\$05\$`)),
+					resource.TestMatchResourceAttr("htpasswd_generated_password.test", "htdigest", regexp.MustCompile(`^alice:private:[0-9a-f]{32}$`)),
+					resource.TestCheckNoResourceAttr("htpasswd_generated_password.test", "apr1"),
+					testAccCheckGeneratedPasswordKept(kept, "result", "sha512"),
+					testAccCheckGeneratedPasswordHashes("htpasswd_generated_password.test"),
+				),
+			},
+			{
+				Config: `
+resource "htpasswd_generated_password" "test" {
+	salt       = "12341234"
+	algorithms = ["sha512"]
+}
+`,
+				ConfigPlanChecks: update,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestMatchResourceAttr("htpasswd_generated_password.test", "sha512", regexp.MustCompile(`^\$6\$12341234\$`)),
+					resource.TestCheckNoResourceAttr("htpasswd_generated_password.test", "bcrypt"),
+					testAccCheckGeneratedPasswordKept(kept, "result"),
+					testAccCheckGeneratedPasswordHashes("htpasswd_generated_password.test"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccResourceGeneratedPassword_Invalid(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: `
+resource "htpasswd_generated_password" "test" {
+	length = 73
+}
+`,
+				ExpectError: regexp.MustCompile(`length must be between 1 and 72, got 73`),
+			},
+			{
+				Config: `
+resource "htpasswd_generated_password" "test" {
+	upper              = false
+	lower              = false
+	special            = false
+	exclude_characters = "0123456789"
+}
+`,
+				ExpectError: regexp.MustCompile(`At least one character class must be enabled`),
+			},
+			{
+				Config: `
+resource "htpasswd_generated_password" "test" {
+	length   = 20
+	wordlist = ["correct", "horse"]
+}
+`,
+				ExpectError: regexp.MustCompile(`length can not be combined with wordlist`),
+			},
+			{
+				Config: `
+resource "htpasswd_generated_password" "test" {
+	wordlist = ["correct", "horse"]
+	words    = 10
+}
+`,
+				ExpectError: regexp.MustCompile(`Invalid Wordlist`),
+			},
+			{
+				Config: `
+resource "htpasswd_generated_password" "test" {
+	bcrypt_cost = 32
+}
+`,
+				PlanOnly:    true,
+				ExpectError: regexp.MustCompile(`bcrypt cost must be between 4 and 31`),
+			},
+		},
+	})
+}
+
+// testAccCheckGeneratedPasswordHashes checks that every hash of the resource
+// verifies against its generated password.
+func testAccCheckGeneratedPasswordHashes(name string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		attributes := s.RootModule().Resources[name].Primary.Attributes
+		opts := HashOptions{
+			Salt:     attributes["salt"],
+			Username: attributes["username"],
+			Realm:    attributes["realm"],
+		}
+		for _, h := range hashers {
+			hash, ok := attributes[h.Name()]
+			if !ok {
+				continue
+			}
+			if !h.Verify(attributes["result"], hash, opts) {
+				return fmt.Errorf("%s = %q does not verify against the result", h.Name(), hash)
+			}
+		}
+		return nil
+	}
+}
+
+// testAccCheckGeneratedPasswordKept stores the named attributes in kept on
+// the first check and checks that they are unchanged by later steps.
+func testAccCheckGeneratedPasswordKept(kept map[string]string, names ...string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		attributes := s.RootModule().Resources["htpasswd_generated_password.test"].Primary.Attributes
+		for _, name := range names {
+			want, ok := kept[name]
+			if !ok {
+				kept[name] = attributes[name]
+				continue
+			}
+			if attributes[name] != want {
+				return fmt.Errorf("%s changed from %q to %q", name, want, attributes[name])
+			}
+		}
+		return nil
+	}
+}
@@ -2,37 +2,139 @@ package htpasswd
 
 import (
 	"context"
+	"crypto/hmac"
+	"crypto/md5"
+	"crypto/pbkdf2"
+	"crypto/rand"
 	"crypto/sha1"
 	"crypto/sha256"
 	"crypto/sha512"
-	"encoding/hex"
+	"crypto/subtle"
 	"encoding/base64"
+	"encoding/hex"
 	"fmt"
+	"hash"
+	"math"
+	"math/bits"
+	"slices"
+	"strconv"
 	"strings"
 
+	xbcrypt "github.com/go-crypt/x/bcrypt"
+	"github.com/go-crypt/x/yescrypt"
+	"github.com/hashicorp/go-uuid"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/boolplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/mapplanmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/johnaoss/htpasswd/apr1"
+	"golang.org/x/crypto/argon2"
 	"golang.org/x/crypto/bcrypt"
+	"golang.org/x/crypto/scrypt"
 )
 
 var _ resource.Resource = &PasswordResource{}
+var _ resource.ResourceWithConfigure = &PasswordResource{}
+var _ resource.ResourceWithImportState = &PasswordResource{}
+var _ resource.ResourceWithUpgradeState = &PasswordResource{}
+var _ resource.ResourceWithValidateConfig = &PasswordResource{}
 
-type PasswordResource struct{}
+type PasswordResource struct {
+	config *providerConfig
+}
 
 type PasswordModel struct {
-	ID         types.String `tfsdk:"id"`
-	Password   types.String `tfsdk:"password"`
-	Salt       types.String `tfsdk:"salt"`
-	LegacyHash types.Bool   `tfsdk:"legacy_hash"`
-	Apr1       types.String `tfsdk:"apr1"`
-	Bcrypt     types.String `tfsdk:"bcrypt"`
-	Sha1       types.String `tfsdk:"sha1"`
-	Sha256     types.String `tfsdk:"sha256"`
-	Sha512     types.String `tfsdk:"sha512"`
+	ID                types.String `tfsdk:"id"`
+	Password          types.String `tfsdk:"password"`
+	PasswordWo        types.String `tfsdk:"password_wo"`
+	PasswordWoVersion types.Int64  `tfsdk:"password_wo_version"`
+	Salt              types.String `tfsdk:"salt"`
+	SaltContext       types.String `tfsdk:"salt_context"`
+	Keepers           types.Map    `tfsdk:"keepers"`
+	LegacyHash        types.Bool   `tfsdk:"legacy_hash"`
+	Sha256Rounds      types.Int64  `tfsdk:"sha256_rounds"`
+	Sha512Rounds      types.Int64  `tfsdk:"sha512_rounds"`
+	BcryptCost        types.Int64  `tfsdk:"bcrypt_cost"`
+	BcryptVariant     types.String `tfsdk:"bcrypt_variant"`
+	Argon2Memory      types.Int64  `tfsdk:"argon2_memory"`
+	Argon2Time        types.Int64  `tfsdk:"argon2_time"`
+	Argon2Parallelism types.Int64  `tfsdk:"argon2_parallelism"`
+	ScryptN           types.Int64  `tfsdk:"scrypt_n"`
+	ScryptR           types.Int64  `tfsdk:"scrypt_r"`
+	ScryptP           types.Int64  `tfsdk:"scrypt_p"`
+	ScryptFormat      types.String `tfsdk:"scrypt_format"`
+	Pbkdf2Iterations  types.Int64  `tfsdk:"pbkdf2_iterations"`
+	Username          types.String `tfsdk:"username"`
+	Realm             types.String `tfsdk:"realm"`
+	Algorithms        types.Set    `tfsdk:"algorithms"`
+	Argon2id          types.String `tfsdk:"argon2id"`
+	Apr1              types.String `tfsdk:"apr1"`
+	Bcrypt            types.String `tfsdk:"bcrypt"`
+	Htdigest          types.String `tfsdk:"htdigest"`
+	Pbkdf2Sha512      types.String `tfsdk:"pbkdf2_sha512"`
+	Scrypt            types.String `tfsdk:"scrypt"`
+	Sha1              types.String `tfsdk:"sha1"`
+	Sha256            types.String `tfsdk:"sha256"`
+	Sha256Crypt       types.String `tfsdk:"sha256_crypt"`
+	Sha512            types.String `tfsdk:"sha512"`
+	Yescrypt          types.String `tfsdk:"yescrypt"`
+}
+
+// hashOptions returns the hash settings configured on the resource.
+func (m *PasswordModel) hashOptions() HashOptions {
+	return HashOptions{
+		Salt:              m.Salt.ValueString(),
+		LegacyHash:        m.LegacyHash.ValueBool(),
+		Sha256Rounds:      m.Sha256Rounds.ValueInt64(),
+		Sha512Rounds:      m.Sha512Rounds.ValueInt64(),
+		BcryptCost:        m.BcryptCost.ValueInt64(),
+		BcryptVariant:     m.BcryptVariant.ValueString(),
+		Argon2Memory:      m.Argon2Memory.ValueInt64(),
+		Argon2Time:        m.Argon2Time.ValueInt64(),
+		Argon2Parallelism: m.Argon2Parallelism.ValueInt64(),
+		ScryptN:           m.ScryptN.ValueInt64(),
+		ScryptR:           m.ScryptR.ValueInt64(),
+		ScryptP:           m.ScryptP.ValueInt64(),
+		ScryptFormat:      m.ScryptFormat.ValueString(),
+		Pbkdf2Iterations:  m.Pbkdf2Iterations.ValueInt64(),
+		Username:          m.Username.ValueString(),
+		Realm:             m.Realm.ValueString(),
+		Algorithms:        algorithmsValue(m.Algorithms),
+	}
+}
+
+// password returns the password to hash. The write-only password_wo is only
+// present in config, so it is taken from there when set.
+func (m *PasswordModel) password(config PasswordModel) string {
+	if !config.PasswordWo.IsNull() {
+		return config.PasswordWo.ValueString()
+	}
+	return m.Password.ValueString()
+}
+
+// hashValues maps the Hasher names to the hash attributes of the model.
+func (m *PasswordModel) hashValues() map[string]*types.String {
+	return map[string]*types.String{
+		"apr1":          &m.Apr1,
+		"argon2id":      &m.Argon2id,
+		"bcrypt":        &m.Bcrypt,
+		"htdigest":      &m.Htdigest,
+		"pbkdf2_sha512": &m.Pbkdf2Sha512,
+		"scrypt":        &m.Scrypt,
+		"sha1":          &m.Sha1,
+		"sha256":        &m.Sha256,
+		"sha256_crypt":  &m.Sha256Crypt,
+		"sha512":        &m.Sha512,
+		"yescrypt":      &m.Yescrypt,
+	}
 }
 
 func NewPasswordResource() resource.Resource {
@@ -45,6 +147,7 @@ func (r *PasswordResource) Metadata(_ context.Context, req resource.MetadataRequ
 
 func (r *PasswordResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
 	resp.Schema = schema.Schema{
+		Version:     1,
 		Description: "Generates htpasswd compatible password hashes",
 		Attributes: map[string]schema.Attribute{
 			"id": schema.StringAttribute{
@@ -55,23 +158,164 @@ func (r *PasswordResource) Schema(_ context.Context, _ resource.SchemaRequest, r
 				},
 			},
 			"password": schema.StringAttribute{
-				Required:    true,
+				Optional:    true,
 				Sensitive:   true,
-				Description: "The password to hash",
+				Description: "The password to hash. Exactly one of password or password_wo must be set.",
 				PlanModifiers: []planmodifier.String{
-					stringplanmodifier.RequiresReplace(),
+					passwordRequiresReplace(),
+				},
+			},
+			"password_wo": schema.StringAttribute{
+				Optional:    true,
+				Sensitive:   true,
+				WriteOnly:   true,
+				Description: "Write-only password to hash, never stored in state. Requires Terraform 1.11 or later and password_wo_version.",
+			},
+			"password_wo_version": schema.Int64Attribute{
+				Optional:    true,
+				Description: "Version of password_wo. Changing it forces replacement, which rehashes the current password_wo, unless the resource was imported and password_wo verifies against the imported hash.",
+				PlanModifiers: []planmodifier.Int64{
+					passwordWoVersionRequiresReplace(),
 				},
 			},
 			"salt": schema.StringAttribute{
 				Optional:    true,
-				Description: "Salt for apr1 and sha512 hashes. Must be exactly 8 characters from the crypt base64 alphabet (unless legacy_hash is true).",
+				Computed:    true,
+				Description: "Salt for apr1, sha256_crypt, sha512, argon2id, pbkdf2_sha512, scrypt and yescrypt hashes. Must be exactly 8 characters from the crypt base64 alphabet (unless legacy_hash is true). When omitted, the salt is derived from salt_context, or a random salt is generated, and kept in state. The pbkdf2_sha512 hash uses the first 16 bytes of the SHA-256 digest of the salt, as SP 800-132 requires salts of at least 128 bits.",
 				PlanModifiers: []planmodifier.String{
-					stringplanmodifier.RequiresReplace(),
+					saltUseState(),
+					stringRequiresReplaceUnlessImported(),
+				},
+			},
+			"salt_context": schema.StringAttribute{
+				Optional:    true,
+				Description: "Context from which the salt is derived with the provider salt_derivation_key, e.g. the username. The same context derives the same salt in every workspace. Can not be combined with salt.",
+				PlanModifiers: []planmodifier.String{
+					stringRequiresReplaceUnlessImported(),
+				},
+			},
+			"keepers": schema.MapAttribute{
+				Optional:    true,
+				ElementType: types.StringType,
+				Description: "Arbitrary map of values that, when changed, forces replacement and generates a new salt when salt is omitted",
+				PlanModifiers: []planmodifier.Map{
+					mapRequiresReplaceUnlessImported(),
 				},
 			},
 			"legacy_hash": schema.BoolAttribute{
 				Optional:    true,
-				Description: "When true, uses pre-1.6.0 salt handling which allows flexible salt lengths (1-16 characters). Use this to maintain compatibility with existing password hashes.",
+				Computed:    true,
+				Description: "When true, uses pre-1.6.0 salt handling which allows flexible salt lengths (1-16 characters). Use this to maintain compatibility with existing password hashes. Set automatically when upgrading state whose apr1 salt is not 8 characters.",
+				PlanModifiers: []planmodifier.Bool{
+					boolplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"sha256_rounds": schema.Int64Attribute{
+				Optional:    true,
+				Description: "Number of rounds for the sha256_crypt hash (1000-999999999). When set, the hash includes a rounds=N$ segment.",
+				PlanModifiers: []planmodifier.Int64{
+					int64RequiresReplaceUnlessImported(),
+				},
+			},
+			"sha512_rounds": schema.Int64Attribute{
+				Optional:    true,
+				Description: "Number of rounds for the sha512 hash (1000-999999999). When set, the hash includes a rounds=N$ segment.",
+				PlanModifiers: []planmodifier.Int64{
+					int64RequiresReplaceUnlessImported(),
+				},
+			},
+			"bcrypt_cost": schema.Int64Attribute{
+				Optional:    true,
+				Description: "Cost factor for the bcrypt hash (4-31). Defaults to 10.",
+				PlanModifiers: []planmodifier.Int64{
+					int64RequiresReplaceUnlessImported(),
+				},
+			},
+			"bcrypt_variant": schema.StringAttribute{
+				Optional:    true,
+				Description: "Prefix variant for the bcrypt hash: 2a, 2b or 2y. Defaults to 2a.",
+				PlanModifiers: []planmodifier.String{
+					stringRequiresReplaceUnlessImported(),
+				},
+			},
+			"argon2_memory": schema.Int64Attribute{
+				Optional:    true,
+				Description: "Memory in KiB used for the argon2id hash. Defaults to 65536.",
+				PlanModifiers: []planmodifier.Int64{
+					int64RequiresReplaceUnlessImported(),
+				},
+			},
+			"argon2_time": schema.Int64Attribute{
+				Optional:    true,
+				Description: "Number of iterations used for the argon2id hash. Defaults to 3.",
+				PlanModifiers: []planmodifier.Int64{
+					int64RequiresReplaceUnlessImported(),
+				},
+			},
+			"argon2_parallelism": schema.Int64Attribute{
+				Optional:    true,
+				Description: "Degree of parallelism used for the argon2id hash (1-255). Defaults to 4.",
+				PlanModifiers: []planmodifier.Int64{
+					int64RequiresReplaceUnlessImported(),
+				},
+			},
+			"scrypt_n": schema.Int64Attribute{
+				Optional:    true,
+				Description: "CPU/memory cost parameter N for the scrypt hash. Must be a power of 2. Defaults to 32768.",
+				PlanModifiers: []planmodifier.Int64{
+					int64RequiresReplaceUnlessImported(),
+				},
+			},
+			"scrypt_r": schema.Int64Attribute{
+				Optional:    true,
+				Description: "Block size parameter r for the scrypt hash. Defaults to 8.",
+				PlanModifiers: []planmodifier.Int64{
+					int64RequiresReplaceUnlessImported(),
+				},
+			},
+			"scrypt_p": schema.Int64Attribute{
+				Optional:    true,
+				Description: "Parallelization parameter p for the scrypt hash. Defaults to 1.",
+				PlanModifiers: []planmodifier.Int64{
+					int64RequiresReplaceUnlessImported(),
+				},
+			},
+			"scrypt_format": schema.StringAttribute{
+				Optional:    true,
+				Description: "Output format of the scrypt hash: phc ($scrypt$ln=...) or crypt ($7$). Defaults to phc.",
+				PlanModifiers: []planmodifier.String{
+					stringRequiresReplaceUnlessImported(),
+				},
+			},
+			"pbkdf2_iterations": schema.Int64Attribute{
+				Optional:    true,
+				Description: "Number of iterations for the pbkdf2_sha512 hash (1000-2147483647). Defaults to 210000.",
+				PlanModifiers: []planmodifier.Int64{
+					int64RequiresReplaceUnlessImported(),
+				},
+			},
+			"username": schema.StringAttribute{
+				Optional:    true,
+				Description: "Username for the htdigest hash. Requires realm.",
+				PlanModifiers: []planmodifier.String{
+					stringRequiresReplaceUnlessImported(),
+				},
+			},
+			"realm": schema.StringAttribute{
+				Optional:    true,
+				Description: "Realm for the htdigest hash. Requires username.",
+				PlanModifiers: []planmodifier.String{
+					stringRequiresReplaceUnlessImported(),
+				},
+			},
+			"algorithms": schema.SetAttribute{
+				Optional:    true,
+				ElementType: types.StringType,
+				Description: "Hashes to compute: " + strings.Join(hasherNames(), ", ") + ". The attributes of other hashes are null. Defaults to the provider algorithms, or all.",
+			},
+			"argon2id": schema.StringAttribute{
+				Computed:    true,
+				Description: "Argon2id hash of the password in PHC string format",
 			},
 			"apr1": schema.StringAttribute{
 				Computed:    true,
@@ -81,6 +325,18 @@ func (r *PasswordResource) Schema(_ context.Context, _ resource.SchemaRequest, r
 				Computed:    true,
 				Description: "Bcrypt hash of the password",
 			},
+			"htdigest": schema.StringAttribute{
+				Computed:    true,
+				Description: "htdigest entry user:realm:MD5(user:realm:password), null unless username and realm are set",
+			},
+			"pbkdf2_sha512": schema.StringAttribute{
+				Computed:    true,
+				Description: "PBKDF2-HMAC-SHA512 hash of the password in passlib $pbkdf2-sha512$ format",
+			},
+			"scrypt": schema.StringAttribute{
+				Computed:    true,
+				Description: "Scrypt hash of the password",
+			},
 			"sha1": schema.StringAttribute{
 				Computed:    true,
 				Description: "SHA1 crypt hash of the password (insecure)",
@@ -89,54 +345,97 @@ func (r *PasswordResource) Schema(_ context.Context, _ resource.SchemaRequest, r
 				Computed:    true,
 				Description: "SHA-256 hash of the password (hex encoded)",
 			},
+			"sha256_crypt": schema.StringAttribute{
+				Computed:    true,
+				Description: "SHA-256 crypt hash of the password",
+			},
 			"sha512": schema.StringAttribute{
 				Computed:    true,
 				Description: "SHA-512 crypt hash of the password",
 			},
+			"yescrypt": schema.StringAttribute{
+				Computed:    true,
+				Description: "Yescrypt hash of the password",
+			},
 		},
 	}
 }
 
-func (r *PasswordResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+func (r *PasswordResource) Configure(_ context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+	config, ok := req.ProviderData.(*providerConfig)
+	if !ok {
+		resp.Diagnostics.AddError("Unexpected Provider Data", fmt.Sprintf("Expected *providerConfig, got %T", req.ProviderData))
+		return
+	}
+	r.config = config
+}
+
+func (r *PasswordResource) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
 	var data PasswordModel
 
-	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
 	if resp.Diagnostics.HasError() {
 		return
 	}
 
-	password := data.Password.ValueString()
-	salt := data.Salt.ValueString()
-	legacyHash := data.LegacyHash.ValueBool()
+	if !data.Password.IsUnknown() && !data.PasswordWo.IsUnknown() && data.Password.IsNull() == data.PasswordWo.IsNull() {
+		resp.Diagnostics.AddAttributeError(path.Root("password"), "Invalid Configuration", "Exactly one of password or password_wo must be set")
+	}
+	if !data.PasswordWo.IsNull() && data.PasswordWoVersion.IsNull() {
+		resp.Diagnostics.AddAttributeError(path.Root("password_wo_version"), "Invalid Configuration", "password_wo_version is required when password_wo is set")
+	}
+	if data.PasswordWo.IsNull() && !data.PasswordWoVersion.IsNull() {
+		resp.Diagnostics.AddAttributeError(path.Root("password_wo_version"), "Invalid Configuration", "password_wo_version requires password_wo")
+	}
+	if !data.Salt.IsNull() && !data.SaltContext.IsNull() {
+		resp.Diagnostics.AddAttributeError(path.Root("salt_context"), "Invalid Configuration", "salt_context can not be combined with salt")
+	}
+	resp.Diagnostics.Append(validateConfigHashOptions(data.hashOptions(), map[string]attr.Value{
+		"legacy_hash":        data.LegacyHash,
+		"username":           data.Username,
+		"realm":              data.Realm,
+		"argon2_parallelism": data.Argon2Parallelism,
+	})...)
+}
 
-	// Validate salt based on legacy_hash setting
-	if err := validateSalt(salt, legacyHash); err != nil {
-		resp.Diagnostics.AddError("Invalid Salt", err.Error())
+func (r *PasswordResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var data, config PasswordModel
+
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+	resp.Diagnostics.Append(req.Config.Get(ctx, &config)...)
+	if resp.Diagnostics.HasError() {
 		return
 	}
 
-	bcryptHash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
-	if err != nil {
-		resp.Diagnostics.AddError("Bcrypt Error", fmt.Sprintf("Failed to generate bcrypt hash: %s", err))
+	if data.LegacyHash.IsUnknown() {
+		data.LegacyHash = types.BoolValue(false)
+	}
+	resp.Diagnostics.Append(r.generateSalt(&data)...)
+	if resp.Diagnostics.HasError() {
 		return
 	}
 
-	apr1Hash, err := apr1.Hash(password, salt)
-	if err != nil {
-		resp.Diagnostics.AddError("APR1 Error", fmt.Sprintf("Failed to generate APR1 hash: %s", err))
+	opts := r.config.hashOptions(data.hashOptions())
+	resp.Diagnostics.Append(validateHashOptions(opts)...)
+	resp.Diagnostics.Append(r.config.validate(opts)...)
+	if resp.Diagnostics.HasError() {
 		return
 	}
 
-	sha512hash := sha512Crypt(password, salt)
-	sha256Hash := sha256Crypt(password, salt)
-	sha1Hash := sha1Crypt(password)
+	resp.Diagnostics.Append(generateHashes(data.password(config), opts, data.hashValues())...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
 
-	data.ID = types.StringValue(fmt.Sprintf("PW%x", string(bcryptHash)))
-	data.Bcrypt = types.StringValue(string(bcryptHash))
-	data.Apr1 = types.StringValue(apr1Hash)
-	data.Sha1 = types.StringValue(sha1Hash)
-	data.Sha256 = types.StringValue(sha256Hash)
-	data.Sha512 = types.StringValue(sha512hash)
+	id, err := uuid.GenerateUUID()
+	if err != nil {
+		resp.Diagnostics.AddError("ID Error", fmt.Sprintf("Failed to generate resource identifier: %s", err))
+		return
+	}
+	data.ID = types.StringValue(id)
 
 	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
 }
@@ -149,39 +448,241 @@ func (r *PasswordResource) Read(ctx context.Context, req resource.ReadRequest, r
 		return
 	}
 
-	password := data.Password.ValueString()
-	salt := data.Salt.ValueString()
+	// Resources imported by hash have no password until the first apply, and
+	// the write-only password_wo is never stored, so there is nothing to
+	// verify the hashes against.
+	if data.Password.IsNull() {
+		return
+	}
 
-	id := data.ID.ValueString()
-	var bcryptString string
-	_, _ = fmt.Sscanf(id, "PW%x", &bcryptString)
+	// Stored hashes are kept as-is as long as they still verify against the
+	// password. Only missing or mismatching hashes are regenerated.
+	resp.Diagnostics.Append(refreshHashes(data.Password.ValueString(), r.config.hashOptions(data.hashOptions()), data.hashValues())...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
 
-	apr1Hash, err := apr1.Hash(password, salt)
-	if err != nil {
-		resp.Diagnostics.AddError("APR1 Error", fmt.Sprintf("Failed to generate APR1 hash: %s", err))
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *PasswordResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var data, state, config PasswordModel
+
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	resp.Diagnostics.Append(req.Config.Get(ctx, &config)...)
+	if resp.Diagnostics.HasError() {
 		return
 	}
 
-	sha512hash := sha512Crypt(password, salt)
+	if data.LegacyHash.IsUnknown() {
+		data.LegacyHash = types.BoolValue(false)
+	}
+	resp.Diagnostics.Append(r.generateSalt(&data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
 
-	h := sha256.New()
-	h.Write([]byte(salt + password))
-	sha256Hash := hex.EncodeToString(h.Sum(nil))
+	opts := r.config.hashOptions(data.hashOptions())
+	resp.Diagnostics.Append(validateHashOptions(opts)...)
+	resp.Diagnostics.Append(r.config.validate(opts)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
 
-	data.Bcrypt = types.StringValue(bcryptString)
-	data.Apr1 = types.StringValue(apr1Hash)
-	data.Sha512 = types.StringValue(sha512hash)
-	data.Sha256 = types.StringValue(sha256Hash)
+	// Hashes in state, such as an imported hash, are kept as long as they
+	// still verify against the password.
+	prior := state.hashValues()
+	for name, value := range data.hashValues() {
+		if value.IsUnknown() {
+			*value = *prior[name]
+		}
+	}
+	resp.Diagnostics.Append(refreshHashes(data.password(config), opts, data.hashValues())...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
 
 	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
 }
 
-func (r *PasswordResource) Update(_ context.Context, _ resource.UpdateRequest, _ *resource.UpdateResponse) {
+// generateSalt sets a salt left unknown by the plan: derived from salt_context
+// when it is set, or random otherwise.
+func (r *PasswordResource) generateSalt(data *PasswordModel) diag.Diagnostics {
+	var diags diag.Diagnostics
+	if !data.Salt.IsUnknown() {
+		return diags
+	}
+	if !data.SaltContext.IsNull() {
+		salt, err := r.config.deriveSalt(data.SaltContext.ValueString())
+		if err != nil {
+			diags.AddAttributeError(path.Root("salt_context"), "Salt Error", err.Error())
+			return diags
+		}
+		data.Salt = types.StringValue(salt)
+		return diags
+	}
+	salt, err := randomSalt(generatedSaltLength)
+	if err != nil {
+		diags.AddError("Salt Error", fmt.Sprintf("Failed to generate salt: %s", err))
+		return diags
+	}
+	data.Salt = types.StringValue(salt)
+	return diags
 }
 
 func (r *PasswordResource) Delete(_ context.Context, _ resource.DeleteRequest, _ *resource.DeleteResponse) {
 }
 
+// ImportState imports an existing hash. The import ID is the hash, e.g. an
+// apr1 or bcrypt string. The hash is kept on the first apply when the
+// configured password verifies against it.
+func (r *PasswordResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	hasher, ok := detectHasher(req.ID)
+	if !ok {
+		resp.Diagnostics.AddError("Unsupported Import ID", fmt.Sprintf("The import ID must be an existing hash starting with one of %s", hashPrefixList()))
+		return
+	}
+	if err := r.config.validateVerify(hasher.Name()); err != nil {
+		resp.Diagnostics.AddError("Unsupported Import ID", err.Error())
+		return
+	}
+
+	id, err := uuid.GenerateUUID()
+	if err != nil {
+		resp.Diagnostics.AddError("ID Error", fmt.Sprintf("Failed to generate resource identifier: %s", err))
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), id)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root(hasher.Name()), req.ID)...)
+}
+
+// importedHash returns the hash of a resource that was imported by hash and
+// has not been applied since. ok is false for all other resources.
+func importedHash(ctx context.Context, state tfsdk.State) (Hasher, string, bool) {
+	if state.Raw.IsNull() {
+		return nil, "", false
+	}
+	var data PasswordModel
+	if diags := state.Get(ctx, &data); diags.HasError() || !data.Password.IsNull() || !data.PasswordWoVersion.IsNull() {
+		return nil, "", false
+	}
+	for _, h := range hashers {
+		if value := data.hashValues()[h.Name()]; !value.IsNull() {
+			return h, value.ValueString(), true
+		}
+	}
+	return nil, "", false
+}
+
+// passwordRequiresReplace requires replacement when the password changes,
+// unless the resource was imported and the password verifies against the
+// imported hash.
+func passwordRequiresReplace() planmodifier.String {
+	return stringplanmodifier.RequiresReplaceIf(
+		func(ctx context.Context, req planmodifier.StringRequest, resp *stringplanmodifier.RequiresReplaceIfFuncResponse) {
+			hasher, hash, imported := importedHash(ctx, req.State)
+			resp.RequiresReplace = !imported || req.PlanValue.IsUnknown() || !hasher.Verify(req.PlanValue.ValueString(), hash, HashOptions{})
+		},
+		"Changing the password forces replacement, unless it verifies against an imported hash.",
+		"Changing the password forces replacement, unless it verifies against an imported hash.",
+	)
+}
+
+// passwordWoVersionRequiresReplace requires replacement when
+// password_wo_version changes, unless the resource was imported and the
+// configured password_wo verifies against the imported hash.
+func passwordWoVersionRequiresReplace() planmodifier.Int64 {
+	return int64planmodifier.RequiresReplaceIf(
+		func(ctx context.Context, req planmodifier.Int64Request, resp *int64planmodifier.RequiresReplaceIfFuncResponse) {
+			hasher, hash, imported := importedHash(ctx, req.State)
+			if !imported {
+				resp.RequiresReplace = true
+				return
+			}
+			var password types.String
+			resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("password_wo"), &password)...)
+			resp.RequiresReplace = password.IsNull() || password.IsUnknown() || !hasher.Verify(password.ValueString(), hash, HashOptions{})
+		},
+		"Changing password_wo_version forces replacement, unless password_wo verifies against an imported hash.",
+		"Changing password_wo_version forces replacement, unless password_wo verifies against an imported hash.",
+	)
+}
+
+// stringRequiresReplaceUnlessImported requires replacement when the value
+// changes, except on the first apply after an import by hash. The settings of
+// an imported resource are adopted from the configuration.
+func stringRequiresReplaceUnlessImported() planmodifier.String {
+	return stringplanmodifier.RequiresReplaceIf(
+		func(ctx context.Context, req planmodifier.StringRequest, resp *stringplanmodifier.RequiresReplaceIfFuncResponse) {
+			_, _, imported := importedHash(ctx, req.State)
+			resp.RequiresReplace = !imported
+		},
+		"Changing this value forces replacement, except on the first apply after an import.",
+		"Changing this value forces replacement, except on the first apply after an import.",
+	)
+}
+
+// int64RequiresReplaceUnlessImported is the Int64 counterpart of
+// stringRequiresReplaceUnlessImported.
+func int64RequiresReplaceUnlessImported() planmodifier.Int64 {
+	return int64planmodifier.RequiresReplaceIf(
+		func(ctx context.Context, req planmodifier.Int64Request, resp *int64planmodifier.RequiresReplaceIfFuncResponse) {
+			_, _, imported := importedHash(ctx, req.State)
+			resp.RequiresReplace = !imported
+		},
+		"Changing this value forces replacement, except on the first apply after an import.",
+		"Changing this value forces replacement, except on the first apply after an import.",
+	)
+}
+
+// mapRequiresReplaceUnlessImported is the Map counterpart of
+// stringRequiresReplaceUnlessImported.
+func mapRequiresReplaceUnlessImported() planmodifier.Map {
+	return mapplanmodifier.RequiresReplaceIf(
+		func(ctx context.Context, req planmodifier.MapRequest, resp *mapplanmodifier.RequiresReplaceIfFuncResponse) {
+			_, _, imported := importedHash(ctx, req.State)
+			resp.RequiresReplace = !imported
+		},
+		"Changing this value forces replacement, except on the first apply after an import.",
+		"Changing this value forces replacement, except on the first apply after an import.",
+	)
+}
+
+// saltUseState keeps the salt of an existing resource when salt is not
+// configured, so a generated salt is stable. Resources without a salt in
+// state, such as those created by earlier versions, keep hashing without one.
+// The salt stays unknown on create, including replacements, and is generated
+// by Create. Resources imported by hash have no salt either, it stays unknown
+// and is generated by Update for the hashes regenerated on the first apply.
+func saltUseState() planmodifier.String {
+	return saltUseStateModifier{}
+}
+
+type saltUseStateModifier struct{}
+
+func (m saltUseStateModifier) Description(_ context.Context) string {
+	return "Keeps the salt in state when salt is not configured."
+}
+
+func (m saltUseStateModifier) MarkdownDescription(ctx context.Context) string {
+	return m.Description(ctx)
+}
+
+func (m saltUseStateModifier) PlanModifyString(ctx context.Context, req planmodifier.StringRequest, resp *planmodifier.StringResponse) {
+	if req.State.Raw.IsNull() || !req.ConfigValue.IsNull() {
+		return
+	}
+	if _, _, imported := importedHash(ctx, req.State); imported && req.StateValue.IsNull() {
+		return
+	}
+	resp.PlanValue = req.StateValue
+}
+
+// generatedSaltLength is the length of salts generated when salt is omitted.
+const generatedSaltLength = 8
+
 // validSaltChars is the crypt-style base64 alphabet used for APR1/MD5-crypt salts
 const validSaltChars = "./0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz"
 
@@ -215,6 +716,581 @@ func validateSalt(salt string, legacyHash bool) error {
 	return nil
 }
 
+// bcryptVariants lists the supported bcrypt hash prefixes. The hashing
+// algorithm is identical for passwords of at most 72 bytes, which is all
+// golang.org/x/crypto/bcrypt accepts.
+var bcryptVariants = []string{"2a", "2b", "2y"}
+
+// validateBcrypt validates the bcrypt cost and variant. A cost of 0 and an
+// empty variant mean the defaults are used.
+func validateBcrypt(cost int64, variant string) error {
+	if cost != 0 && (cost < int64(bcrypt.MinCost) || cost > int64(bcrypt.MaxCost)) {
+		return fmt.Errorf("bcrypt cost must be between %d and %d, got %d", bcrypt.MinCost, bcrypt.MaxCost, cost)
+	}
+	if variant != "" && !slices.Contains(bcryptVariants, variant) {
+		return fmt.Errorf("bcrypt variant must be one of %s, got %q", strings.Join(bcryptVariants, ", "), variant)
+	}
+	return nil
+}
+
+// bcryptSaltLength is the length of an encoded bcrypt salt.
+const bcryptSaltLength = 22
+
+// validateBcryptSalt validates an encoded bcrypt salt. An empty salt means a
+// random salt is used.
+func validateBcryptSalt(salt string) error {
+	if salt == "" {
+		return nil
+	}
+	if len(salt) != bcryptSaltLength {
+		return fmt.Errorf("bcrypt salt must be exactly %d characters long, got %d", bcryptSaltLength, len(salt))
+	}
+	if _, err := xbcrypt.Base64Decode([]byte(salt)); err != nil {
+		return fmt.Errorf("bcrypt salt contains invalid characters, only characters in the set [./A-Za-z0-9] are allowed")
+	}
+	return nil
+}
+
+// bcryptGenerate generates a bcrypt hash with the given cost and variant prefix.
+// An empty salt generates a random salt, otherwise salt must be an encoded
+// bcrypt salt.
+func bcryptGenerate(password string, cost int64, variant, salt string) (string, error) {
+	if cost == 0 {
+		cost = int64(bcrypt.DefaultCost)
+	}
+	var hash []byte
+	var err error
+	if salt == "" {
+		hash, err = bcrypt.GenerateFromPassword([]byte(password), int(cost))
+	} else {
+		hash, err = bcryptGenerateSalt(password, int(cost), salt)
+	}
+	if err != nil {
+		return "", err
+	}
+	if variant == "" {
+		return string(hash), nil
+	}
+	return "$" + variant + string(hash[3:]), nil
+}
+
+// bcryptGenerateSalt generates a bcrypt hash using the encoded salt.
+func bcryptGenerateSalt(password string, cost int, salt string) ([]byte, error) {
+	if len(password) > 72 {
+		return nil, bcrypt.ErrPasswordTooLong
+	}
+	rawSalt, err := xbcrypt.Base64Decode([]byte(salt))
+	if err != nil {
+		return nil, err
+	}
+	return xbcrypt.GenerateFromPasswordSalt([]byte(password), rawSalt, cost)
+}
+
+// validateHtdigest validates the htdigest username and realm. Both must be set
+// for an htdigest entry, or neither.
+func validateHtdigest(username, realm string) error {
+	if (username == "") != (realm == "") {
+		return fmt.Errorf("username and realm must be set together")
+	}
+	if strings.ContainsAny(username, ":\r\n") || strings.ContainsAny(realm, ":\r\n") {
+		return fmt.Errorf("username and realm must not contain colons or line breaks")
+	}
+	return nil
+}
+
+// htdigestDigest returns the hex encoded MD5 digest of an htdigest entry.
+func htdigestDigest(username, realm, password string) string {
+	sum := md5.Sum([]byte(username + ":" + realm + ":" + password))
+	return hex.EncodeToString(sum[:])
+}
+
+// htdigestEntry returns the htdigest file entry user:realm:digest.
+func htdigestEntry(username, realm, password string) string {
+	return username + ":" + realm + ":" + htdigestDigest(username, realm, password)
+}
+
+// Argon2id defaults follow the second recommended option of RFC 9106 section 4,
+// scaled down to 64 MiB of memory.
+const (
+	argon2DefaultMemory      = 64 * 1024
+	argon2DefaultTime        = 3
+	argon2DefaultParallelism = 4
+	argon2KeyLength          = 32
+	argon2SaltLength         = 16
+)
+
+// validateArgon2 validates the argon2id parameters. A value of 0 means the
+// default is used.
+func validateArgon2(memory, time, parallelism int64) error {
+	if time < 0 || time > math.MaxUint32 {
+		return fmt.Errorf("argon2 time must be between 1 and %d, got %d", uint32(math.MaxUint32), time)
+	}
+	if parallelism < 0 || parallelism > math.MaxUint8 {
+		return fmt.Errorf("argon2 parallelism must be between 1 and %d, got %d", math.MaxUint8, parallelism)
+	}
+	if parallelism == 0 {
+		parallelism = argon2DefaultParallelism
+	}
+	if memory != 0 && (memory < 8*parallelism || memory > math.MaxUint32) {
+		return fmt.Errorf("argon2 memory must be between %d and %d KiB, got %d", 8*parallelism, uint32(math.MaxUint32), memory)
+	}
+	return nil
+}
+
+// argon2idGenerate generates a PHC formatted argon2id hash. When salt is empty a
+// random salt is generated.
+func argon2idGenerate(password, salt string, memory, time, parallelism int64) (string, error) {
+	if memory == 0 {
+		memory = argon2DefaultMemory
+	}
+	if time == 0 {
+		time = argon2DefaultTime
+	}
+	if parallelism == 0 {
+		parallelism = argon2DefaultParallelism
+	}
+
+	saltBytes := []byte(salt)
+	if len(saltBytes) == 0 {
+		saltBytes = make([]byte, argon2SaltLength)
+		if _, err := rand.Read(saltBytes); err != nil {
+			return "", err
+		}
+	}
+
+	key := argon2.IDKey([]byte(password), saltBytes, uint32(time), uint32(memory), uint8(parallelism), argon2KeyLength)
+
+	return fmt.Sprintf("$argon2id$v=%d$m=%d,t=%d,p=%d$%s$%s",
+		argon2.Version, memory, time, parallelism,
+		base64.RawStdEncoding.EncodeToString(saltBytes),
+		base64.RawStdEncoding.EncodeToString(key)), nil
+}
+
+// pbkdf2 defaults follow the OWASP recommendation for PBKDF2-HMAC-SHA512 and
+// the salt and key sizes of passlib.
+const (
+	pbkdf2DefaultIterations = 210000
+	pbkdf2SaltLength        = 16
+	pbkdf2KeyLength         = 64
+)
+
+// pbkdf2Encoding is the adapted base64 encoding of passlib, which uses . in
+// place of + and no padding.
+var pbkdf2Encoding = base64.NewEncoding("ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz0123456789./").WithPadding(base64.NoPadding)
+
+// validatePbkdf2 validates the PBKDF2 iterations. A value of 0 means the
+// default is used.
+func validatePbkdf2(iterations int64) error {
+	if iterations != 0 && (iterations < 1000 || iterations > math.MaxInt32) {
+		return fmt.Errorf("pbkdf2 iterations must be between 1000 and %d, got %d", math.MaxInt32, iterations)
+	}
+	return nil
+}
+
+// pbkdf2Generate generates a passlib compatible $pbkdf2-sha512$ hash. When salt
+// is empty a random salt is generated. SP 800-132 requires salts of at least
+// 128 bits, which Go enforces in FIPS 140-only mode, so shorter salts are
+// replaced by the first pbkdf2SaltLength bytes of their SHA-256 digest.
+func pbkdf2Generate(password, salt string, iterations int64) (string, error) {
+	if iterations == 0 {
+		iterations = pbkdf2DefaultIterations
+	}
+
+	saltBytes := []byte(salt)
+	switch {
+	case len(saltBytes) == 0:
+		saltBytes = make([]byte, pbkdf2SaltLength)
+		if _, err := rand.Read(saltBytes); err != nil {
+			return "", err
+		}
+	case len(saltBytes) < pbkdf2SaltLength:
+		sum := sha256.Sum256(saltBytes)
+		saltBytes = sum[:pbkdf2SaltLength]
+	}
+	return pbkdf2Hash(password, saltBytes, iterations)
+}
+
+// pbkdf2Hash returns the $pbkdf2-sha512$ hash of password with salt used as-is.
+func pbkdf2Hash(password string, salt []byte, iterations int64) (string, error) {
+	key, err := pbkdf2.Key(sha512.New, password, salt, int(iterations), pbkdf2KeyLength)
+	if err != nil {
+		return "", err
+	}
+
+	return fmt.Sprintf("$pbkdf2-sha512$%d$%s$%s", iterations,
+		pbkdf2Encoding.EncodeToString(salt),
+		pbkdf2Encoding.EncodeToString(key)), nil
+}
+
+// scrypt defaults match the interactive login recommendation of the scrypt
+// package documentation.
+const (
+	scryptDefaultN   = 32768
+	scryptDefaultR   = 8
+	scryptDefaultP   = 1
+	scryptKeyLength  = 32
+	scryptSaltLength = 16
+)
+
+// Supported scrypt output formats. scryptFormatPHC produces
+// $scrypt$ln=...,r=...,p=...$salt$hash strings as used by passlib and the PHC
+// string format, scryptFormatCrypt produces libxcrypt compatible $7$ strings.
+const (
+	scryptFormatPHC   = "phc"
+	scryptFormatCrypt = "crypt"
+)
+
+// validateScrypt validates the scrypt parameters and format. A value of 0 or
+// an empty format means the default is used.
+func validateScrypt(n, r, p int64, format string) error {
+	if n != 0 && (n < 2 || n&(n-1) != 0 || n > 1<<62) {
+		return fmt.Errorf("scrypt N must be a power of 2 greater than 1, got %d", n)
+	}
+	if r < 0 || r > math.MaxInt32 {
+		return fmt.Errorf("scrypt r must be between 1 and %d, got %d", math.MaxInt32, r)
+	}
+	if p < 0 || p > math.MaxInt32 {
+		return fmt.Errorf("scrypt p must be between 1 and %d, got %d", math.MaxInt32, p)
+	}
+	if format != "" && format != scryptFormatPHC && format != scryptFormatCrypt {
+		return fmt.Errorf("scrypt format must be one of %s, %s, got %q", scryptFormatPHC, scryptFormatCrypt, format)
+	}
+	return nil
+}
+
+// scryptGenerate generates an scrypt hash in the requested format. When salt is
+// empty a random salt is generated.
+func scryptGenerate(password, salt string, n, r, p int64, format string) (string, error) {
+	if n == 0 {
+		n = scryptDefaultN
+	}
+	if r == 0 {
+		r = scryptDefaultR
+	}
+	if p == 0 {
+		p = scryptDefaultP
+	}
+	ln := bits.TrailingZeros64(uint64(n))
+
+	if format == scryptFormatCrypt {
+		if salt == "" {
+			var err error
+			if salt, err = randomSalt(scryptSaltLength); err != nil {
+				return "", err
+			}
+		}
+		key, err := scrypt.Key([]byte(password), []byte(salt), int(n), int(r), int(p), scryptKeyLength)
+		if err != nil {
+			return "", err
+		}
+		return "$7$" + string(validSaltChars[ln]) + cryptEncodeUint30(uint32(r)) + cryptEncodeUint30(uint32(p)) +
+			salt + "$" + cryptEncodeBytes(key), nil
+	}
+
+	saltBytes := []byte(salt)
+	if len(saltBytes) == 0 {
+		saltBytes = make([]byte, scryptSaltLength)
+		if _, err := rand.Read(saltBytes); err != nil {
+			return "", err
+		}
+	}
+	key, err := scrypt.Key([]byte(password), saltBytes, int(n), int(r), int(p), scryptKeyLength)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("$scrypt$ln=%d,r=%d,p=%d$%s$%s", ln, r, p,
+		base64.RawStdEncoding.EncodeToString(saltBytes),
+		base64.RawStdEncoding.EncodeToString(key)), nil
+}
+
+// cryptEncodeUint30 encodes a 30 bit value as 5 characters of the crypt base64
+// alphabet, least significant 6 bits first.
+func cryptEncodeUint30(val uint32) string {
+	encoded := ""
+	for i := 0; i < 5; i++ {
+		encoded += string(validSaltChars[val&0x3f])
+		val >>= 6
+	}
+	return encoded
+}
+
+// cryptEncodeBytes encodes src using the little-endian crypt base64 encoding
+// used by scrypt and yescrypt.
+func cryptEncodeBytes(src []byte) string {
+	encoded := ""
+	for i := 0; i < len(src); i += 3 {
+		var b1, b2 byte
+		n := 2
+		if i+1 < len(src) {
+			b1 = src[i+1]
+			n = 3
+		}
+		if i+2 < len(src) {
+			b2 = src[i+2]
+			n = 4
+		}
+		encoded += b64From24Bit(b2, b1, src[i], n)
+	}
+	return encoded
+}
+
+// cryptDecodeUint30 decodes 5 characters of the crypt base64 alphabet into a
+// 30 bit value, least significant 6 bits first.
+func cryptDecodeUint30(src string) (uint32, bool) {
+	var val uint32
+	for i := len(src) - 1; i >= 0; i-- {
+		c := strings.IndexByte(validSaltChars, src[i])
+		if c < 0 {
+			return 0, false
+		}
+		val = val<<6 | uint32(c)
+	}
+	return val, true
+}
+
+// randomSalt generates a random salt of n characters from validSaltChars.
+func randomSalt(n int) (string, error) {
+	b := make([]byte, n)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	for i := range b {
+		b[i] = validSaltChars[int(b[i])%len(validSaltChars)]
+	}
+	return string(b), nil
+}
+
+// deriveSalt derives a salt of generatedSaltLength characters from
+// validSaltChars as the HMAC-SHA256 of context keyed with key. The alphabet has
+// 64 characters, so mapping the HMAC bytes onto it is unbiased.
+func deriveSalt(key, context string) string {
+	mac := hmac.New(sha256.New, []byte(key))
+	mac.Write([]byte(context))
+	sum := mac.Sum(nil)
+	b := make([]byte, generatedSaltLength)
+	for i := range b {
+		b[i] = validSaltChars[int(sum[i])%len(validSaltChars)]
+	}
+	return string(b)
+}
+
+// deriveBcryptSalt derives an encoded bcrypt salt from context keyed with key.
+// The HMAC input is prefixed so that it is independent of the salt returned by
+// deriveSalt for the same context.
+func deriveBcryptSalt(key, context string) string {
+	mac := hmac.New(sha256.New, []byte(key))
+	mac.Write([]byte("bcrypt:" + context))
+	return string(xbcrypt.Base64Encode(mac.Sum(nil)[:16]))
+}
+
+// yescryptDefaultSetting is the yescrypt parameter prefix generated by
+// libxcrypt by default (N=4096, r=32, p=1).
+const yescryptDefaultSetting = "$y$j9T$"
+
+// yescryptGenerate generates a libxcrypt compatible yescrypt hash. The salt is
+// used as the encoded salt string; when empty a random 16 byte salt is
+// generated.
+func yescryptGenerate(password, salt string) (string, error) {
+	if salt == "" {
+		b := make([]byte, 16)
+		if _, err := rand.Read(b); err != nil {
+			return "", err
+		}
+		salt = cryptEncodeBytes(b)
+	}
+	hash, err := yescrypt.Hash([]byte(password), []byte(yescryptDefaultSetting+salt))
+	if err != nil {
+		return "", err
+	}
+	return string(hash), nil
+}
+
+// verifyEqual compares a freshly computed hash with a stored hash in constant
+// time.
+func verifyEqual(computed, hash string) bool {
+	return subtle.ConstantTimeCompare([]byte(computed), []byte(hash)) == 1
+}
+
+// verifyApr1 reports whether hash is the APR1-MD5 hash of password.
+func verifyApr1(password, hash string) bool {
+	parts := strings.Split(hash, "$")
+	if len(parts) != 4 || parts[1] != "apr1" {
+		return false
+	}
+	computed, err := apr1.Hash(password, parts[2])
+	if err != nil {
+		return false
+	}
+	return verifyEqual(computed, hash)
+}
+
+// verifyBcrypt reports whether hash is a bcrypt hash of password.
+func verifyBcrypt(password, hash string) bool {
+	if cost, err := bcrypt.Cost([]byte(hash)); err != nil || cost > bcryptVerifyMaxCost {
+		return false
+	}
+	return bcrypt.CompareHashAndPassword([]byte(hash), []byte(password)) == nil
+}
+
+// verifySha1 reports whether hash is the {SHA} hash of password.
+func verifySha1(password, hash string) bool {
+	return verifyEqual(sha1Crypt(password), hash)
+}
+
+// verifySHACrypt reports whether hash is a SHA-256 ($5$) or SHA-512 ($6$)
+// crypt hash of password.
+func verifySHACrypt(password, hash string) bool {
+	parts := strings.Split(hash, "$")
+	if len(parts) < 4 {
+		return false
+	}
+
+	var rounds int64
+	if strings.HasPrefix(parts[2], "rounds=") {
+		n, err := strconv.ParseInt(strings.TrimPrefix(parts[2], "rounds="), 10, 64)
+		if err != nil || validateRounds(n) != nil || n > shaCryptVerifyMaxRounds || len(parts) != 5 {
+			return false
+		}
+		rounds = n
+		parts = append(parts[:2], parts[3:]...)
+	}
+	if len(parts) != 4 {
+		return false
+	}
+
+	switch parts[1] {
+	case "5":
+		return verifyEqual(sha256Crypt(password, parts[2], rounds), hash)
+	case "6":
+		return verifyEqual(sha512Crypt(password, parts[2], rounds), hash)
+	}
+	return false
+}
+
+// Upper bounds of the cost parameters accepted when verifying a hash. Hashes
+// can come from untrusted input, such as an import ID or a data source, and
+// must not exhaust the memory or time of Terraform. They are well above the
+// defaults and the recommendations of RFC 9106, RFC 7914 and OWASP, and keep a
+// single verification around a second.
+const (
+	argon2VerifyMaxMemory     = 1 << 20 // KiB
+	argon2VerifyMaxTime       = 64
+	bcryptVerifyMaxCost       = 14
+	pbkdf2VerifyMaxIterations = 1_000_000
+	scryptVerifyMaxMemory     = 1 << 30 // bytes, 128 * r * N
+	scryptVerifyMaxP          = 16
+	shaCryptVerifyMaxRounds   = 1_000_000
+)
+
+// verifyArgon2id reports whether hash is a PHC formatted argon2id hash of
+// password.
+func verifyArgon2id(password, hash string) bool {
+	parts := strings.Split(hash, "$")
+	if len(parts) != 6 || parts[1] != "argon2id" || parts[2] != fmt.Sprintf("v=%d", argon2.Version) {
+		return false
+	}
+	var memory, time, parallelism int64
+	if _, err := fmt.Sscanf(parts[3], "m=%d,t=%d,p=%d", &memory, &time, &parallelism); err != nil {
+		return false
+	}
+	if memory <= 0 || time <= 0 || parallelism <= 0 || validateArgon2(memory, time, parallelism) != nil {
+		return false
+	}
+	if memory > argon2VerifyMaxMemory || time > argon2VerifyMaxTime {
+		return false
+	}
+	salt, err := base64.RawStdEncoding.DecodeString(parts[4])
+	if err != nil || len(salt) == 0 {
+		return false
+	}
+	computed, err := argon2idGenerate(password, string(salt), memory, time, parallelism)
+	if err != nil {
+		return false
+	}
+	return verifyEqual(computed, hash)
+}
+
+func verifyPbkdf2(password, hash string) bool {
+	parts := strings.Split(hash, "$")
+	if len(parts) != 5 || parts[1] != "pbkdf2-sha512" {
+		return false
+	}
+	iterations, err := strconv.ParseInt(parts[2], 10, 64)
+	if err != nil || iterations <= 0 || validatePbkdf2(iterations) != nil || iterations > pbkdf2VerifyMaxIterations {
+		return false
+	}
+	salt, err := pbkdf2Encoding.DecodeString(parts[3])
+	if err != nil || len(salt) == 0 {
+		return false
+	}
+	// Hashes of other tools may use salts shorter than 128 bits, which must
+	// be verified as they are rather than stretched like generated salts.
+	computed, err := pbkdf2Hash(password, salt, iterations)
+	if err != nil {
+		return false
+	}
+	return verifyEqual(computed, hash)
+}
+
+// verifyScrypt reports whether hash is an scrypt hash of password in either
+// the PHC ($scrypt$) or crypt ($7$) format.
+func verifyScrypt(password, hash string) bool {
+	var ln, r, p int64
+	var salt, format string
+
+	switch {
+	case strings.HasPrefix(hash, "$scrypt$"):
+		parts := strings.Split(hash, "$")
+		if len(parts) != 5 {
+			return false
+		}
+		if _, err := fmt.Sscanf(parts[2], "ln=%d,r=%d,p=%d", &ln, &r, &p); err != nil {
+			return false
+		}
+		saltBytes, err := base64.RawStdEncoding.DecodeString(parts[3])
+		if err != nil || len(saltBytes) == 0 {
+			return false
+		}
+		salt, format = string(saltBytes), scryptFormatPHC
+	case strings.HasPrefix(hash, "$7$"):
+		end := strings.LastIndexByte(hash, '$')
+		if len(hash) < 14 || end < 14 {
+			return false
+		}
+		rv, rok := cryptDecodeUint30(hash[4:9])
+		pv, pok := cryptDecodeUint30(hash[9:14])
+		if !rok || !pok {
+			return false
+		}
+		ln = int64(strings.IndexByte(validSaltChars, hash[3]))
+		r, p = int64(rv), int64(pv)
+		salt, format = hash[14:end], scryptFormatCrypt
+	default:
+		return false
+	}
+
+	if ln < 1 || ln > 62 || r < 1 || p < 1 || salt == "" || validateScrypt(1<<ln, r, p, format) != nil {
+		return false
+	}
+	if ln > 30 || r > scryptVerifyMaxMemory/128 || 128*r<<ln > scryptVerifyMaxMemory || p > scryptVerifyMaxP {
+		return false
+	}
+	computed, err := scryptGenerate(password, salt, 1<<ln, r, p, format)
+	if err != nil {
+		return false
+	}
+	return verifyEqual(computed, hash)
+}
+
+// verifyYescrypt reports whether hash is a yescrypt hash of password.
+func verifyYescrypt(password, hash string) bool {
+	computed, err := yescrypt.Hash([]byte(password), []byte(hash))
+	if err != nil {
+		return false
+	}
+	return verifyEqual(string(computed), hash)
+}
+
 // The SHA-1 algorithm doesn't use any salt and is considered insecure.
 func sha1Crypt(password string) string {
 	const prefix = "{SHA}"
@@ -225,29 +1301,89 @@ func sha1Crypt(password string) string {
 	return prefix + hash
 }
 
-func sha256Crypt(password, salt string) string {
+// sha256Digest returns the hex encoded SHA-256 digest of salt+password. This
+// is not a crypt format and is kept for backwards compatibility only.
+func sha256Digest(password, salt string) string {
 	h := sha256.New()
 	h.Write([]byte(salt + password))
 	hash := hex.EncodeToString(h.Sum(nil))
 	return hash
 }
 
-// sha512Crypt implements the SHA-512 crypt algorithm as specified in
+// sha256Crypt implements the SHA-256 crypt algorithm as specified in
 // http://www.akkadia.org/drepper/SHA-crypt.txt
-func sha512Crypt(password, salt string) string {
-	const rounds = 5000
-	const prefix = "$6$"
-
-	// Custom base64 alphabet used by crypt
-	const alphabet = "./0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz"
-
+// A rounds value of 0 uses the default without a rounds= segment.
+func sha256Crypt(password, salt string, rounds int64) string {
 	// Ensure salt is maximum 16 characters
 	if len(salt) > 16 {
 		salt = salt[:16]
 	}
 
+	prefix, n := shaCryptPrefix("$5$", rounds)
+	result := shaCryptDigest(sha256.New, password, salt, n)
+
+	// Specific byte reordering for SHA-256 crypt as per specification
+	indices := [][3]int{
+		{0, 10, 20}, {21, 1, 11}, {12, 22, 2}, {3, 13, 23}, {24, 4, 14},
+		{15, 25, 5}, {6, 16, 26}, {27, 7, 17}, {18, 28, 8}, {9, 19, 29},
+	}
+
+	encoded := ""
+	for _, idx := range indices {
+		encoded += b64From24Bit(result[idx[0]], result[idx[1]], result[idx[2]], 4)
+	}
+	encoded += b64From24Bit(0, result[31], result[30], 3)
+
+	return prefix + salt + "$" + encoded
+}
+
+// b64From24Bit encodes three bytes as n characters of the crypt base64
+// alphabet, least significant 6 bits first.
+func b64From24Bit(b2, b1, b0 byte, n int) string {
+	val := (int(b2) << 16) | (int(b1) << 8) | int(b0)
+	encoded := ""
+	for i := 0; i < n; i++ {
+		encoded += string(validSaltChars[val&0x3f])
+		val >>= 6
+	}
+	return encoded
+}
+
+// SHA-crypt rounds limits as specified in the SHA-crypt specification.
+// shaCryptDefaultRounds is used when no rounds= parameter is present.
+const (
+	shaCryptDefaultRounds = 5000
+	shaCryptMinRounds     = 1000
+	shaCryptMaxRounds     = 999999999
+)
+
+// validateRounds validates an explicitly configured SHA-crypt rounds value.
+// A value of 0 means the rounds were not configured.
+func validateRounds(rounds int64) error {
+	if rounds == 0 {
+		return nil
+	}
+	if rounds < shaCryptMinRounds || rounds > shaCryptMaxRounds {
+		return fmt.Errorf("rounds must be between %d and %d, got %d", shaCryptMinRounds, shaCryptMaxRounds, rounds)
+	}
+	return nil
+}
+
+// shaCryptPrefix returns the hash prefix including the optional rounds=N$
+// segment, and the number of rounds to perform.
+func shaCryptPrefix(id string, rounds int64) (string, int) {
+	if rounds == 0 {
+		return id, shaCryptDefaultRounds
+	}
+	return fmt.Sprintf("%srounds=%d$", id, rounds), int(rounds)
+}
+
+// shaCryptDigest computes the raw SHA-crypt digest shared by the SHA-256 and
+// SHA-512 variants. Only the hash function and final encoding differ.
+func shaCryptDigest(newHash func() hash.Hash, password, salt string, rounds int) []byte {
 	// Step 1: Compute alternate sum
-	h := sha512.New()
+	h := newHash()
+	size := h.Size()
 	h.Write([]byte(password))
 	h.Write([]byte(salt))
 	h.Write([]byte(password))
@@ -259,8 +1395,8 @@ func sha512Crypt(password, salt string) string {
 	h.Write([]byte(salt))
 
 	// Add altResult for each character in password
-	for i := len(password); i > 0; i -= 64 {
-		if i > 64 {
+	for i := len(password); i > 0; i -= size {
+		if i > size {
 			h.Write(altResult)
 		} else {
 			h.Write(altResult[:i])
@@ -287,8 +1423,8 @@ func sha512Crypt(password, salt string) string {
 
 	// Create P sequence
 	p := make([]byte, 0, len(password))
-	for i := len(password); i > 0; i -= 64 {
-		if i > 64 {
+	for i := len(password); i > 0; i -= size {
+		if i > size {
 			p = append(p, pBytes...)
 		} else {
 			p = append(p, pBytes[:i]...)
@@ -304,8 +1440,8 @@ func sha512Crypt(password, salt string) string {
 
 	// Create S sequence
 	s := make([]byte, 0, len(salt))
-	for i := len(salt); i > 0; i -= 64 {
-		if i > 64 {
+	for i := len(salt); i > 0; i -= size {
+		if i > size {
 			s = append(s, sBytes...)
 		} else {
 			s = append(s, sBytes[:i]...)
@@ -339,50 +1475,35 @@ func sha512Crypt(password, salt string) string {
 		result = h.Sum(nil)
 	}
 
-	// Step 6: Encode result using the SHA-512 crypt base64 encoding
-	// This follows the exact specification for SHA-512 crypt encoding
-	encoded := ""
+	return result
+}
+
+// sha512Crypt implements the SHA-512 crypt algorithm as specified in
+// http://www.akkadia.org/drepper/SHA-crypt.txt
+// A rounds value of 0 uses the default without a rounds= segment.
+func sha512Crypt(password, salt string, rounds int64) string {
+	// Ensure salt is maximum 16 characters
+	if len(salt) > 16 {
+		salt = salt[:16]
+	}
+
+	prefix, n := shaCryptPrefix("$6$", rounds)
+	result := shaCryptDigest(sha512.New, password, salt, n)
 
 	// Specific byte reordering for SHA-512 crypt as per specification
-	indices := []int{
-		0, 21, 42, 22, 43, 1, 44, 2, 23, 3, 24, 45, 25, 46, 4, 47, 5, 26,
-		6, 27, 48, 28, 49, 7, 50, 8, 29, 9, 30, 51, 31, 52, 10, 53, 11, 32,
-		12, 33, 54, 34, 55, 13, 56, 14, 35, 15, 36, 57, 37, 58, 16, 59, 17, 38,
-		18, 39, 60, 40, 61, 19, 62, 20, 41,
-	}
-
-	// Process in groups of 3 bytes
-	for i := 0; i < len(indices); i += 3 {
-		var val int
-		if i+2 < len(indices) {
-			// Standard 3-byte group
-			val = (int(result[indices[i]]) << 16) |
-				(int(result[indices[i+1]]) << 8) |
-				int(result[indices[i+2]])
-			// Encode as 4 characters
-			for j := 0; j < 4; j++ {
-				encoded += string(alphabet[val&0x3f])
-				val >>= 6
-			}
-		} else {
-			// Handle remaining bytes
-			for j := i; j < len(indices) && j < i+3; j++ {
-				val = (val << 8) | int(result[indices[j]])
-			}
-			// Encode based on how many bytes we have
-			chars := ((len(indices)-i)*8 + 5) / 6
-			for j := 0; j < chars; j++ {
-				encoded += string(alphabet[val&0x3f])
-				val >>= 6
-			}
-		}
+	indices := [][3]int{
+		{0, 21, 42}, {22, 43, 1}, {44, 2, 23}, {3, 24, 45}, {25, 46, 4},
+		{47, 5, 26}, {6, 27, 48}, {28, 49, 7}, {50, 8, 29}, {9, 30, 51},
+		{31, 52, 10}, {53, 11, 32}, {12, 33, 54}, {34, 55, 13}, {56, 14, 35},
+		{15, 36, 57}, {37, 58, 16}, {59, 17, 38}, {18, 39, 60}, {40, 61, 19},
+		{62, 20, 41},
 	}
 
-	// Handle the final byte (index 63)
-	val := int(result[63])
-	encoded += string(alphabet[val&0x3f])
-	val >>= 6
-	encoded += string(alphabet[val&0x3f])
+	encoded := ""
+	for _, idx := range indices {
+		encoded += b64From24Bit(result[idx[0]], result[idx[1]], result[idx[2]], 4)
+	}
+	encoded += b64From24Bit(0, 0, result[63], 2)
 
 	return prefix + salt + "$" + encoded
 }
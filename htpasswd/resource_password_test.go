@@ -5,7 +5,11 @@ import (
 	"regexp"
 	"testing"
 
+	"github.com/hashicorp/go-version"
 	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/plancheck"
+	"github.com/hashicorp/terraform-plugin-testing/terraform"
+	"github.com/hashicorp/terraform-plugin-testing/tfversion"
 )
 
 func TestAccResourcePassword_Complete(t *testing.T) {
@@ -19,6 +23,7 @@ func TestAccResourcePassword_Complete(t *testing.T) {
 					resource.TestCheckResourceAttr("htpasswd_password.test_1", "salt", "saltySal"),
 					resource.TestCheckResourceAttrSet("htpasswd_password.test_1", "sha1"),
 					resource.TestCheckResourceAttrSet("htpasswd_password.test_1", "sha256"),
+					resource.TestCheckResourceAttrSet("htpasswd_password.test_1", "sha256_crypt"),
 					resource.TestCheckResourceAttrSet("htpasswd_password.test_1", "sha512"),
 					resource.TestCheckResourceAttrSet("htpasswd_password.test_1", "apr1"),
 					resource.TestCheckResourceAttrSet("htpasswd_password.test_1", "bcrypt"),
@@ -28,6 +33,9 @@ func TestAccResourcePassword_Complete(t *testing.T) {
 					// Check sha1 format: should start with {SHA}
 					resource.TestMatchResourceAttr("htpasswd_password.test_1", "sha1",
 						regexp.MustCompile(`^{SHA}.+`)),
+					// Check sha256_crypt format: should start with $5$saltySal$
+					resource.TestMatchResourceAttr("htpasswd_password.test_1", "sha256_crypt",
+						regexp.MustCompile(`^\$5\$saltySal\$.+`)),
 					// Check sha512 format: should start with $6$saltySal$
 					resource.TestMatchResourceAttr("htpasswd_password.test_1", "sha512",
 						regexp.MustCompile(`^\$6\$saltySal\$.+`)),
@@ -43,6 +51,7 @@ func TestAccResourcePassword_Complete(t *testing.T) {
 					resource.TestCheckResourceAttr("htpasswd_password.test_2", "salt", "12341234"),
 					resource.TestCheckResourceAttrSet("htpasswd_password.test_2", "sha1"),
 					resource.TestCheckResourceAttrSet("htpasswd_password.test_2", "sha256"),
+					resource.TestCheckResourceAttrSet("htpasswd_password.test_2", "sha256_crypt"),
 					resource.TestCheckResourceAttrSet("htpasswd_password.test_2", "sha512"),
 					resource.TestCheckResourceAttrSet("htpasswd_password.test_2", "apr1"),
 					resource.TestCheckResourceAttrSet("htpasswd_password.test_2", "bcrypt"),
@@ -52,6 +61,9 @@ func TestAccResourcePassword_Complete(t *testing.T) {
 					// Check sha1 format: should start with {SHA}
 					resource.TestMatchResourceAttr("htpasswd_password.test_2", "sha1",
 						regexp.MustCompile(`^{SHA}.+`)),
+					// Check sha256_crypt format: should start with $5$12341234$
+					resource.TestMatchResourceAttr("htpasswd_password.test_2", "sha256_crypt",
+						regexp.MustCompile(`^\$5\$12341234\$.+`)),
 					// Check sha512 format: should start with $6$12341234$
 					resource.TestMatchResourceAttr("htpasswd_password.test_2", "sha512",
 						regexp.MustCompile(`^\$6\$12341234\$.+`)),
@@ -98,6 +110,835 @@ func TestAccResourcePassword_SHA512Regression(t *testing.T) {
 	})
 }
 
+func TestAccResourcePassword_SHA256CryptKnownAnswer(t *testing.T) {
+	// Expected values are taken from OpenSSL:
+	//   openssl passwd -5 -salt 12341234 1234567890abcdefghijklmnopqrstuvwxyz
+	//   openssl passwd -5 -salt saltySal secret123
+	expectedLong := "$5$12341234$qazLJlCfUJRwis7t./V6dAsV7XYFBIcRgcxFIVSVNG9"
+	expectedShort := "$5$saltySal$4Ty5aUmBWGOEccaQocjg2RAQ6V3ISGcIXzqmeGQqsuC"
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccResourcePasswordConfig("sha256_long", "1234567890abcdefghijklmnopqrstuvwxyz", "12341234") +
+					testAccResourcePasswordConfig("sha256_short", "secret123", "saltySal"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("htpasswd_password.test_sha256_long", "sha256_crypt", expectedLong),
+					resource.TestCheckResourceAttr("htpasswd_password.test_sha256_short", "sha256_crypt", expectedShort),
+				),
+			},
+		},
+	})
+}
+
+func TestAccResourcePassword_Rounds(t *testing.T) {
+	// Expected values are taken from OpenSSL:
+	//   openssl passwd -5 -salt 'rounds=1000$saltySal' secret123
+	//   openssl passwd -6 -salt 'rounds=100000$12341234' secret123
+	expectedSHA256 := "$5$rounds=1000$saltySal$EVSFEd9pwEGvE7v0ceuYb5xLIcMCKvju5xmNm1ivjQ5"
+	expectedSHA512 := "$6$rounds=100000$12341234$gCCybFnOp4SRlwpV6fDDQv3uXM1s4JHhuaP3oSdN3Y5O0wJSfgLM3CJGgr/It3JlSv/QAiM11MkkzvUjXTHXm."
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: `
+resource "htpasswd_password" "sha256_rounds" {
+	password      = "secret123"
+	salt          = "saltySal"
+	sha256_rounds = 1000
+}
+
+resource "htpasswd_password" "sha512_rounds" {
+	password      = "secret123"
+	salt          = "12341234"
+	sha512_rounds = 100000
+}
+`,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("htpasswd_password.sha256_rounds", "sha256_crypt", expectedSHA256),
+					resource.TestCheckResourceAttr("htpasswd_password.sha512_rounds", "sha512", expectedSHA512),
+				),
+			},
+		},
+	})
+}
+
+func TestAccResourcePassword_InvalidRounds(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: `
+resource "htpasswd_password" "invalid_rounds" {
+	password      = "secret123"
+	salt          = "saltySal"
+	sha512_rounds = 999
+}
+`,
+				ExpectError: regexp.MustCompile(`rounds must be between 1000 and 999999999`),
+			},
+			{
+				Config: `
+resource "htpasswd_password" "invalid_rounds" {
+	password          = "secret123"
+	salt              = "saltySal"
+	pbkdf2_iterations = 999
+}
+`,
+				ExpectError: regexp.MustCompile(`pbkdf2 iterations must be between 1000`),
+			},
+		},
+	})
+}
+
+func TestAccResourcePassword_InvalidSettingsOnPlan(t *testing.T) {
+	steps := []struct {
+		setting string
+		err     string
+	}{
+		{`bcrypt_cost = 3`, `bcrypt cost must be between 4 and 31`},
+		{`salt = "short"`, `salt must be exactly 8 characters`},
+		{`argon2_memory = 16`, `argon2 memory must be between 32 and`},
+		{`scrypt_n = 1000`, `Invalid Scrypt Settings`},
+		{`username = "alice"`, `username and realm must be set together`},
+	}
+
+	var testSteps []resource.TestStep
+	for _, step := range steps {
+		testSteps = append(testSteps, resource.TestStep{
+			Config: fmt.Sprintf(`
+resource "htpasswd_password" "test" {
+	password = "secret123"
+	%s
+}
+`, step.setting),
+			PlanOnly:    true,
+			ExpectError: regexp.MustCompile(step.err),
+		})
+	}
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps:                    testSteps,
+	})
+}
+
+func TestAccResourcePassword_BcryptCostAndVariant(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config:      testAccResourcePasswordBcryptConfig(32, "2b"),
+				ExpectError: regexp.MustCompile(`bcrypt cost must be between 4 and 31`),
+			},
+			{
+				Config:      testAccResourcePasswordBcryptConfig(12, "2x"),
+				ExpectError: regexp.MustCompile(`bcrypt variant must be one of 2a, 2b, 2y`),
+			},
+			{
+				Config: testAccResourcePasswordBcryptConfig(12, "2y"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("htpasswd_password.bcrypt", "bcrypt_cost", "12"),
+					resource.TestCheckResourceAttr("htpasswd_password.bcrypt", "bcrypt_variant", "2y"),
+					resource.TestMatchResourceAttr("htpasswd_password.bcrypt", "bcrypt",
+						regexp.MustCompile(`^\$2y\$12\$.{53}$`)),
+				),
+			},
+			{
+				Config: testAccResourcePasswordBcryptConfig(4, "2b"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestMatchResourceAttr("htpasswd_password.bcrypt", "bcrypt",
+						regexp.MustCompile(`^\$2b\$04\$.{53}$`)),
+				),
+			},
+		},
+	})
+}
+
+func testAccResourcePasswordBcryptConfig(cost int, variant string) string {
+	return fmt.Sprintf(`
+resource "htpasswd_password" "bcrypt" {
+	password       = "secret123"
+	bcrypt_cost    = %d
+	bcrypt_variant = "%s"
+}
+`, cost, variant)
+}
+
+func TestAccResourcePassword_Argon2id(t *testing.T) {
+	// Expected value is taken from the Argon2 reference implementation test suite:
+	//   password = "password", salt = "somesalt", m = 65536, t = 2, p = 1
+	expectedArgon2id := "$argon2id$v=19$m=65536,t=2,p=1$c29tZXNhbHQ$CTFhFdXPJO1aFaMaO6Mm5c8y7cJHAph8ArZWb2GRPPc"
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: `
+resource "htpasswd_password" "argon2id" {
+	password           = "password"
+	salt               = "somesalt"
+	argon2_memory      = 65536
+	argon2_time        = 2
+	argon2_parallelism = 1
+}
+
+resource "htpasswd_password" "argon2id_random_salt" {
+	password = "password"
+}
+`,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("htpasswd_password.argon2id", "argon2id", expectedArgon2id),
+					resource.TestMatchResourceAttr("htpasswd_password.argon2id_random_salt", "argon2id",
+						regexp.MustCompile(`^\$argon2id\$v=19\$m=65536,t=3,p=4\$[A-Za-z0-9+/]{11}\$[A-Za-z0-9+/]{43}$`)),
+				),
+			},
+		},
+	})
+}
+
+func TestAccResourcePassword_Scrypt(t *testing.T) {
+	// Expected values are taken from Python:
+	//   hashlib.scrypt(b"password", salt=b"saltySal", n=16384, r=8, p=1, dklen=32)
+	//   crypt.crypt("password", "$7$C6..../....saltySal") (libxcrypt)
+	expectedPHC := "$scrypt$ln=14,r=8,p=1$c2FsdHlTYWw$7f43sACRI+xY9sWXNFnuZOZSjTKeFyaM3hIJsIGQkqo"
+	expectedCrypt := "$7$C6..../....saltySal$hvzBk0EYXkCKqLwZoYZvYNiIB8XbLM0XS9F0k46YGe8"
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config:      testAccResourcePasswordScryptConfig("passlib"),
+				ExpectError: regexp.MustCompile(`scrypt format must be one of phc, crypt`),
+			},
+			{
+				Config: testAccResourcePasswordScryptConfig("phc") + testAccResourcePasswordScryptConfig("crypt"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("htpasswd_password.scrypt_phc", "scrypt", expectedPHC),
+					resource.TestCheckResourceAttr("htpasswd_password.scrypt_crypt", "scrypt", expectedCrypt),
+				),
+			},
+		},
+	})
+}
+
+func testAccResourcePasswordScryptConfig(format string) string {
+	return fmt.Sprintf(`
+resource "htpasswd_password" "scrypt_%s" {
+	password      = "password"
+	salt          = "saltySal"
+	scrypt_n      = 16384
+	scrypt_format = "%s"
+}
+`, format, format)
+}
+
+func TestAccResourcePassword_Yescrypt(t *testing.T) {
+	// Expected value is taken from libxcrypt:
+	//   crypt.crypt("password", "$y$j9T$saltySal")
+	expectedYescrypt := "$y$j9T$saltySal$IhSdlMOWhMvju7xEQ.Xx3c.t372QErgDynv0Zw7pkOB"
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccResourcePasswordConfig("yescrypt", "password", "saltySal") + `
+resource "htpasswd_password" "yescrypt_random_salt" {
+	password = "password"
+}
+`,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("htpasswd_password.test_yescrypt", "yescrypt", expectedYescrypt),
+					resource.TestMatchResourceAttr("htpasswd_password.yescrypt_random_salt", "yescrypt",
+						regexp.MustCompile(`^\$y\$j9T\$[./0-9A-Za-z]{8}\$[./0-9A-Za-z]{43}$`)),
+				),
+			},
+		},
+	})
+}
+
+func TestAccResourcePassword_StableAcrossRefresh(t *testing.T) {
+	hashes := map[string]string{}
+	attributes := []string{"apr1", "argon2id", "bcrypt", "scrypt", "sha1", "sha256", "sha256_crypt", "sha512", "yescrypt"}
+
+	captureHashes := func(s *terraform.State) error {
+		rs := s.RootModule().Resources["htpasswd_password.stable"]
+		for _, attr := range attributes {
+			hashes[attr] = rs.Primary.Attributes[attr]
+		}
+		return nil
+	}
+	compareHashes := func(s *terraform.State) error {
+		rs := s.RootModule().Resources["htpasswd_password.stable"]
+		for _, attr := range attributes {
+			if got := rs.Primary.Attributes[attr]; got != hashes[attr] {
+				return fmt.Errorf("%s changed after refresh: %q != %q", attr, got, hashes[attr])
+			}
+		}
+		return nil
+	}
+
+	config := `
+resource "htpasswd_password" "stable" {
+	password = "secret123"
+}
+`
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: config,
+				Check: resource.ComposeTestCheckFunc(
+					// The ID must not leak any hash of the password
+					resource.TestMatchResourceAttr("htpasswd_password.stable", "id",
+						regexp.MustCompile(`^[0-9a-f]{8}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{12}$`)),
+					captureHashes,
+				),
+			},
+			{
+				RefreshState: true,
+				Check:        compareHashes,
+			},
+			{
+				Config:   config,
+				PlanOnly: true,
+			},
+		},
+	})
+}
+
+func TestAccResourcePassword_Htdigest(t *testing.T) {
+	// Expected value is the format written by Apache htdigest:
+	//   printf 'alice:private:secret123' | md5sum
+	expectedHtdigest := "alice:private:4577980e2c512b92b0c67633674f8f07"
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccResourcePasswordConfig("no_realm", "secret123", "saltySal") + `
+resource "htpasswd_password" "test_htdigest" {
+	password = "secret123"
+	username = "alice"
+	realm    = "private"
+}
+`,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("htpasswd_password.test_htdigest", "htdigest", expectedHtdigest),
+					resource.TestCheckNoResourceAttr("htpasswd_password.test_no_realm", "htdigest"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccResourcePassword_HtdigestWithoutRealm(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: `
+resource "htpasswd_password" "test" {
+	password = "secret123"
+	username = "alice"
+}
+`,
+				ExpectError: regexp.MustCompile(`username and realm must be set together`),
+			},
+		},
+	})
+}
+
+func TestAccResourcePassword_Import(t *testing.T) {
+	imported := "$apr1$saltySal$a2VqVYQs.Pn9WnYqB9arf."
+	config := `
+resource "htpasswd_password" "test" {
+	password = "password"
+	salt     = "saltySal"
+}
+`
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config:             config,
+				ResourceName:       "htpasswd_password.test",
+				ImportState:        true,
+				ImportStateId:      imported,
+				ImportStatePersist: true,
+			},
+			{
+				Config: config,
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectResourceAction("htpasswd_password.test", plancheck.ResourceActionUpdate),
+					},
+				},
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("htpasswd_password.test", "apr1", imported),
+					resource.TestCheckResourceAttr("htpasswd_password.test", "password", "password"),
+					resource.TestCheckResourceAttr("htpasswd_password.test", "sha512", "$6$saltySal$XdaqwyhKeIQCQ9/QllKL6szC6D5C6y8F5X78/0hhKqL17qQLpmNjWTF6aNVX1J0nDJFINnVkjajC1u7fJXpFF1"),
+				),
+			},
+			{
+				Config:   config,
+				PlanOnly: true,
+			},
+		},
+	})
+}
+
+func TestAccResourcePassword_ImportWrongPassword(t *testing.T) {
+	config := `
+resource "htpasswd_password" "test" {
+	password = "not the password"
+}
+`
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config:             config,
+				ResourceName:       "htpasswd_password.test",
+				ImportState:        true,
+				ImportStateId:      "$2y$05$CCCCCCCCCCCCCCCCCCCCC.E5YPO9kmyuRGyh0XouQYb4YMJKvyOeW",
+				ImportStatePersist: true,
+			},
+			{
+				Config: config,
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectResourceAction("htpasswd_password.test", plancheck.ResourceActionReplace),
+					},
+				},
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestMatchResourceAttr("htpasswd_password.test", "bcrypt", regexp.MustCompile(`^\$2a\$10\$`)),
+				),
+			},
+		},
+	})
+}
+
+func TestAccResourcePassword_ImportGeneratesSalt(t *testing.T) {
+	var salt string
+	imported := "{SHA}W6ph5Mm5Pz8GgiULbPgzG37mj9g="
+	config := `
+resource "htpasswd_password" "test" {
+	password = "password"
+}
+`
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config:             config,
+				ResourceName:       "htpasswd_password.test",
+				ImportState:        true,
+				ImportStateId:      imported,
+				ImportStatePersist: true,
+			},
+			{
+				Config: config,
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectResourceAction("htpasswd_password.test", plancheck.ResourceActionUpdate),
+					},
+				},
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("htpasswd_password.test", "sha1", imported),
+					resource.TestMatchResourceAttr("htpasswd_password.test", "salt", regexp.MustCompile(`^[./0-9A-Za-z]{8}$`)),
+					resource.TestMatchResourceAttr("htpasswd_password.test", "sha512", regexp.MustCompile(`^\$6\$[./0-9A-Za-z]{8}\$`)),
+					resource.TestMatchResourceAttr("htpasswd_password.test", "sha256_crypt", regexp.MustCompile(`^\$5\$[./0-9A-Za-z]{8}\$`)),
+					testAccCheckGeneratedSalt(&salt),
+				),
+			},
+			{
+				Config:   config,
+				PlanOnly: true,
+			},
+		},
+	})
+}
+
+func TestAccResourcePassword_ImportDerivesSalt(t *testing.T) {
+	var salt string
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config:             testAccResourcePasswordSaltContextConfig("alice"),
+				ResourceName:       "htpasswd_password.test",
+				ImportState:        true,
+				ImportStateId:      "{SHA}W6ph5Mm5Pz8GgiULbPgzG37mj9g=",
+				ImportStatePersist: true,
+			},
+			{
+				Config: testAccResourcePasswordSaltContextConfig("alice"),
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectResourceAction("htpasswd_password.test", plancheck.ResourceActionUpdate),
+					},
+				},
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("htpasswd_password.test", "salt", "lvubSiXh"),
+					testAccCheckGeneratedSalt(&salt),
+				),
+			},
+		},
+	})
+}
+
+func TestAccResourcePassword_ImportUnsupportedID(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config:        testAccResourcePasswordConfig("import", "password", "saltySal"),
+				ResourceName:  "htpasswd_password.test_import",
+				ImportState:   true,
+				ImportStateId: "password",
+				ExpectError:   regexp.MustCompile(`Unsupported Import ID`),
+			},
+		},
+	})
+}
+
+func TestAccResourcePassword_GeneratedSalt(t *testing.T) {
+	var salt string
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccResourcePasswordKeepersConfig("1"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestMatchResourceAttr("htpasswd_password.test", "salt", regexp.MustCompile(`^[./0-9A-Za-z]{8}$`)),
+					testAccCheckGeneratedSalt(&salt),
+				),
+			},
+			{
+				Config:   testAccResourcePasswordKeepersConfig("1"),
+				PlanOnly: true,
+			},
+			{
+				Config: testAccResourcePasswordKeepersConfig("2"),
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectResourceAction("htpasswd_password.test", plancheck.ResourceActionReplace),
+					},
+				},
+				Check: resource.ComposeTestCheckFunc(
+					func(s *terraform.State) error {
+						if s.RootModule().Resources["htpasswd_password.test"].Primary.Attributes["salt"] == salt {
+							return fmt.Errorf("salt %q was not regenerated", salt)
+						}
+						return nil
+					},
+					testAccCheckGeneratedSalt(&salt),
+				),
+			},
+		},
+	})
+}
+
+func testAccResourcePasswordKeepersConfig(rotation string) string {
+	return fmt.Sprintf(`
+resource "htpasswd_password" "test" {
+	password = "password"
+
+	keepers = {
+		rotation = %q
+	}
+}
+`, rotation)
+}
+
+// testAccCheckGeneratedSalt checks that the salted hashes use the salt in
+// state and stores the salt in salt.
+func testAccCheckGeneratedSalt(salt *string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		attributes := s.RootModule().Resources["htpasswd_password.test"].Primary.Attributes
+		*salt = attributes["salt"]
+		for _, name := range []string{"apr1", "sha256_crypt", "sha512", "yescrypt"} {
+			if info := describeHash(attributes[name]); info.Salt != *salt {
+				return fmt.Errorf("%s = %q does not use salt %q", name, attributes[name], *salt)
+			}
+		}
+		return nil
+	}
+}
+
+func TestAccResourcePassword_DerivedSalt(t *testing.T) {
+	var salt string
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccResourcePasswordSaltContextConfig("alice"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("htpasswd_password.test", "salt", "lvubSiXh"),
+					testAccCheckGeneratedSalt(&salt),
+				),
+			},
+			{
+				Config:   testAccResourcePasswordSaltContextConfig("alice"),
+				PlanOnly: true,
+			},
+			{
+				Config: testAccResourcePasswordSaltContextConfig("bob"),
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectResourceAction("htpasswd_password.test", plancheck.ResourceActionReplace),
+					},
+				},
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("htpasswd_password.test", "salt", "Z/WvxbQZ"),
+					testAccCheckGeneratedSalt(&salt),
+				),
+			},
+		},
+	})
+}
+
+func TestAccResourcePassword_DerivedSaltInvalid(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: `
+resource "htpasswd_password" "test" {
+	password     = "password"
+	salt_context = "alice"
+}
+`,
+				ExpectError: regexp.MustCompile(`salt_context requires the provider salt_derivation_key`),
+			},
+			{
+				Config: `
+resource "htpasswd_password" "test" {
+	password     = "password"
+	salt         = "saltySal"
+	salt_context = "alice"
+}
+`,
+				ExpectError: regexp.MustCompile(`salt_context can not be combined with salt`),
+			},
+		},
+	})
+}
+
+func testAccResourcePasswordSaltContextConfig(context string) string {
+	return fmt.Sprintf(`
+provider "htpasswd" {
+	salt_derivation_key = "correct horse battery staple"
+}
+
+resource "htpasswd_password" "test" {
+	password     = "password"
+	salt_context = %q
+}
+`, context)
+}
+
+func TestAccResourcePassword_Algorithms(t *testing.T) {
+	var bcryptHash string
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config:      testAccResourcePasswordAlgorithmsConfig(`["bcrypt", "md5"]`),
+				ExpectError: regexp.MustCompile(`Invalid Algorithm`),
+			},
+			{
+				Config: testAccResourcePasswordAlgorithmsConfig(`["bcrypt"]`),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestMatchResourceAttr("htpasswd_password.test", "bcrypt", regexp.MustCompile(`^\$2a\$04\$`)),
+					resource.TestCheckNoResourceAttr("htpasswd_password.test", "apr1"),
+					resource.TestCheckNoResourceAttr("htpasswd_password.test", "argon2id"),
+					resource.TestCheckNoResourceAttr("htpasswd_password.test", "sha512"),
+					func(s *terraform.State) error {
+						bcryptHash = s.RootModule().Resources["htpasswd_password.test"].Primary.Attributes["bcrypt"]
+						return nil
+					},
+				),
+			},
+			{
+				Config: testAccResourcePasswordAlgorithmsConfig(`["bcrypt", "sha512"]`),
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectResourceAction("htpasswd_password.test", plancheck.ResourceActionUpdate),
+					},
+				},
+				Check: resource.ComposeTestCheckFunc(
+					func(s *terraform.State) error {
+						return resource.TestCheckResourceAttr("htpasswd_password.test", "bcrypt", bcryptHash)(s)
+					},
+					resource.TestCheckResourceAttr("htpasswd_password.test", "sha512", "$6$saltySal$XdaqwyhKeIQCQ9/QllKL6szC6D5C6y8F5X78/0hhKqL17qQLpmNjWTF6aNVX1J0nDJFINnVkjajC1u7fJXpFF1"),
+					resource.TestCheckNoResourceAttr("htpasswd_password.test", "apr1"),
+				),
+			},
+		},
+	})
+}
+
+func testAccResourcePasswordAlgorithmsConfig(algorithms string) string {
+	return fmt.Sprintf(`
+resource "htpasswd_password" "test" {
+	password    = "password"
+	salt        = "saltySal"
+	bcrypt_cost = 4
+	algorithms  = %s
+}
+`, algorithms)
+}
+
+func TestAccResourcePassword_WriteOnly(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		TerraformVersionChecks: []tfversion.TerraformVersionCheck{
+			tfversion.SkipBelow(version.Must(version.NewVersion("1.11.0"))),
+		},
+		Steps: []resource.TestStep{
+			{
+				Config: testAccResourcePasswordWriteOnlyConfig("password", 1),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckNoResourceAttr("htpasswd_password.test", "password"),
+					resource.TestCheckNoResourceAttr("htpasswd_password.test", "password_wo"),
+					resource.TestCheckResourceAttr("htpasswd_password.test", "password_wo_version", "1"),
+					resource.TestCheckResourceAttr("htpasswd_password.test", "apr1", "$apr1$saltySal$a2VqVYQs.Pn9WnYqB9arf."),
+				),
+			},
+			{
+				// Changing password_wo alone is not detected.
+				Config:   testAccResourcePasswordWriteOnlyConfig("secret123", 1),
+				PlanOnly: true,
+			},
+			{
+				Config: testAccResourcePasswordWriteOnlyConfig("secret123", 2),
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectResourceAction("htpasswd_password.test", plancheck.ResourceActionReplace),
+					},
+				},
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("htpasswd_password.test", "password_wo_version", "2"),
+					resource.TestCheckResourceAttr("htpasswd_password.test", "sha256_crypt", "$5$saltySal$4Ty5aUmBWGOEccaQocjg2RAQ6V3ISGcIXzqmeGQqsuC"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccResourcePassword_WriteOnlyImport(t *testing.T) {
+	imported := "$apr1$saltySal$a2VqVYQs.Pn9WnYqB9arf."
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		TerraformVersionChecks: []tfversion.TerraformVersionCheck{
+			tfversion.SkipBelow(version.Must(version.NewVersion("1.11.0"))),
+		},
+		Steps: []resource.TestStep{
+			{
+				Config:             testAccResourcePasswordWriteOnlyConfig("password", 1),
+				ResourceName:       "htpasswd_password.test",
+				ImportState:        true,
+				ImportStateId:      imported,
+				ImportStatePersist: true,
+			},
+			{
+				Config: testAccResourcePasswordWriteOnlyConfig("password", 1),
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectResourceAction("htpasswd_password.test", plancheck.ResourceActionUpdate),
+					},
+				},
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("htpasswd_password.test", "apr1", imported),
+					resource.TestCheckResourceAttr("htpasswd_password.test", "password_wo_version", "1"),
+					resource.TestCheckResourceAttr("htpasswd_password.test", "sha512", "$6$saltySal$XdaqwyhKeIQCQ9/QllKL6szC6D5C6y8F5X78/0hhKqL17qQLpmNjWTF6aNVX1J0nDJFINnVkjajC1u7fJXpFF1"),
+				),
+			},
+			{
+				Config:   testAccResourcePasswordWriteOnlyConfig("password", 1),
+				PlanOnly: true,
+			},
+		},
+	})
+}
+
+func TestAccResourcePassword_WriteOnlyImportWrongPassword(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		TerraformVersionChecks: []tfversion.TerraformVersionCheck{
+			tfversion.SkipBelow(version.Must(version.NewVersion("1.11.0"))),
+		},
+		Steps: []resource.TestStep{
+			{
+				Config:             testAccResourcePasswordWriteOnlyConfig("not the password", 1),
+				ResourceName:       "htpasswd_password.test",
+				ImportState:        true,
+				ImportStateId:      "$apr1$saltySal$a2VqVYQs.Pn9WnYqB9arf.",
+				ImportStatePersist: true,
+			},
+			{
+				Config: testAccResourcePasswordWriteOnlyConfig("not the password", 1),
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectResourceAction("htpasswd_password.test", plancheck.ResourceActionReplace),
+					},
+				},
+			},
+		},
+	})
+}
+
+func TestAccResourcePassword_WriteOnlyInvalid(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		TerraformVersionChecks: []tfversion.TerraformVersionCheck{
+			tfversion.SkipBelow(version.Must(version.NewVersion("1.11.0"))),
+		},
+		Steps: []resource.TestStep{
+			{
+				Config: `
+resource "htpasswd_password" "test" {
+	password    = "password"
+	password_wo = "password"
+}
+`,
+				ExpectError: regexp.MustCompile(`Exactly one of password or password_wo must be set`),
+			},
+			{
+				Config: `
+resource "htpasswd_password" "test" {
+	password_wo = "password"
+}
+`,
+				ExpectError: regexp.MustCompile(`password_wo_version is required when password_wo is set`),
+			},
+		},
+	})
+}
+
+func testAccResourcePasswordWriteOnlyConfig(password string, passwordVersion int) string {
+	return fmt.Sprintf(`
+resource "htpasswd_password" "test" {
+	password_wo         = %q
+	password_wo_version = %d
+	salt                = "saltySal"
+}
+`, password, passwordVersion)
+}
+
 func testAccResourcePasswordConfig(postfix, password, salt string) string {
 	return fmt.Sprintf(`
 resource "htpasswd_password" "test_%s" {
@@ -0,0 +1,124 @@
+package htpasswd
+
+import (
+	"context"
+	"encoding/hex"
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/go-uuid"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// passwordModelV0 is the state of htpasswd_password before schema versioning.
+type passwordModelV0 struct {
+	ID         types.String `tfsdk:"id"`
+	Password   types.String `tfsdk:"password"`
+	Salt       types.String `tfsdk:"salt"`
+	LegacyHash types.Bool   `tfsdk:"legacy_hash"`
+	Apr1       types.String `tfsdk:"apr1"`
+	Bcrypt     types.String `tfsdk:"bcrypt"`
+	Sha1       types.String `tfsdk:"sha1"`
+	Sha256     types.String `tfsdk:"sha256"`
+	Sha512     types.String `tfsdk:"sha512"`
+}
+
+// passwordSchemaV0 returns the schema of htpasswd_password before schema
+// versioning. Only the attribute types matter for upgrading state.
+func passwordSchemaV0() *schema.Schema {
+	return &schema.Schema{
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Computed: true,
+			},
+			"password": schema.StringAttribute{
+				Required:  true,
+				Sensitive: true,
+			},
+			"salt": schema.StringAttribute{
+				Optional: true,
+			},
+			"legacy_hash": schema.BoolAttribute{
+				Optional: true,
+			},
+			"apr1": schema.StringAttribute{
+				Computed: true,
+			},
+			"bcrypt": schema.StringAttribute{
+				Computed: true,
+			},
+			"sha1": schema.StringAttribute{
+				Computed: true,
+			},
+			"sha256": schema.StringAttribute{
+				Computed: true,
+			},
+			"sha512": schema.StringAttribute{
+				Computed: true,
+			},
+		},
+	}
+}
+
+func (r *PasswordResource) UpgradeState(_ context.Context) map[int64]resource.StateUpgrader {
+	return map[int64]resource.StateUpgrader{
+		0: {
+			PriorSchema:   passwordSchemaV0(),
+			StateUpgrader: upgradePasswordStateV0,
+		},
+	}
+}
+
+// upgradePasswordStateV0 migrates state written before schema versioning. The
+// PW prefixed hex encoded bcrypt hash ID is replaced by a UUID, and legacy_hash
+// is set when the salt or the stored apr1 hash predate the 1.6.0 salt rules, so
+// that existing hashes are kept instead of failing validation.
+func upgradePasswordStateV0(ctx context.Context, req resource.UpgradeStateRequest, resp *resource.UpgradeStateResponse) {
+	var prior passwordModelV0
+
+	resp.Diagnostics.Append(req.State.Get(ctx, &prior)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	id, err := uuid.GenerateUUID()
+	if err != nil {
+		resp.Diagnostics.AddError("ID Error", fmt.Sprintf("Failed to generate resource identifier: %s", err))
+		return
+	}
+
+	data := PasswordModel{
+		ID:          types.StringValue(id),
+		Password:    prior.Password,
+		Salt:        prior.Salt,
+		SaltContext: types.StringNull(),
+		Keepers:     types.MapNull(types.StringType),
+		Algorithms:  types.SetNull(types.StringType),
+		LegacyHash:  prior.LegacyHash,
+		Apr1:        prior.Apr1,
+		Bcrypt:      prior.Bcrypt,
+		Sha1:        prior.Sha1,
+		Sha256:      prior.Sha256,
+		Sha512:      prior.Sha512,
+	}
+
+	// Early versions only kept the bcrypt hash in the ID.
+	if data.Bcrypt.ValueString() == "" {
+		data.Bcrypt = types.StringNull()
+		if encoded, ok := strings.CutPrefix(prior.ID.ValueString(), "PW"); ok {
+			if decoded, err := hex.DecodeString(encoded); err == nil && len(decoded) > 0 {
+				data.Bcrypt = types.StringValue(string(decoded))
+			}
+		}
+	}
+
+	if !data.LegacyHash.ValueBool() {
+		salt := data.Salt.ValueString()
+		info := describeHash(data.Apr1.ValueString())
+		data.LegacyHash = types.BoolValue((salt != "" && len(salt) != 8) || (info.Algorithm == "apr1" && len(info.Salt) != 8))
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
@@ -0,0 +1,110 @@
+package htpasswd
+
+import (
+	"context"
+	"encoding/hex"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+func TestPasswordResource_UpgradeStateV0(t *testing.T) {
+	const bcryptHash = "$2a$05$CCCCCCCCCCCCCCCCCCCCC.E5YPO9kmyuRGyh0XouQYb4YMJKvyOeW"
+
+	tests := []struct {
+		name       string
+		id         string
+		salt       string
+		legacyHash bool
+		apr1       string
+		bcrypt     string
+		wantLegacy bool
+		wantBcrypt string
+	}{
+		{
+			name:       "modern salt",
+			salt:       "saltySal",
+			apr1:       "$apr1$saltySal$a2VqVYQs.Pn9WnYqB9arf.",
+			bcrypt:     bcryptHash,
+			wantBcrypt: bcryptHash,
+		},
+		{
+			name:       "short salt",
+			salt:       "salt",
+			apr1:       "$apr1$salt$Xxd1irWT9ycqoYxGFn4cb.",
+			bcrypt:     bcryptHash,
+			wantLegacy: true,
+			wantBcrypt: bcryptHash,
+		},
+		{
+			name:       "legacy hash already set",
+			salt:       "salt",
+			legacyHash: true,
+			apr1:       "$apr1$salt$Xxd1irWT9ycqoYxGFn4cb.",
+			bcrypt:     bcryptHash,
+			wantLegacy: true,
+			wantBcrypt: bcryptHash,
+		},
+		{
+			name:       "bcrypt from id",
+			id:         "PW" + hex.EncodeToString([]byte(bcryptHash)),
+			apr1:       "$apr1$saltySal$a2VqVYQs.Pn9WnYqB9arf.",
+			wantBcrypt: bcryptHash,
+		},
+	}
+
+	ctx := context.Background()
+	r := &PasswordResource{}
+	var schemaResp resource.SchemaResponse
+	r.Schema(ctx, resource.SchemaRequest{}, &schemaResp)
+	prior := passwordSchemaV0()
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			id := tt.id
+			if id == "" {
+				id = "PW" + hex.EncodeToString([]byte(tt.bcrypt))
+			}
+			raw := tftypes.NewValue(prior.Type().TerraformType(ctx), map[string]tftypes.Value{
+				"id":          tftypes.NewValue(tftypes.String, id),
+				"password":    tftypes.NewValue(tftypes.String, "password"),
+				"salt":        tftypes.NewValue(tftypes.String, tt.salt),
+				"legacy_hash": tftypes.NewValue(tftypes.Bool, tt.legacyHash),
+				"apr1":        tftypes.NewValue(tftypes.String, tt.apr1),
+				"bcrypt":      tftypes.NewValue(tftypes.String, tt.bcrypt),
+				"sha1":        tftypes.NewValue(tftypes.String, "{SHA}W6ph5Mm5Pz8GgiULbPgzG37mj9g="),
+				"sha256":      tftypes.NewValue(tftypes.String, ""),
+				"sha512":      tftypes.NewValue(tftypes.String, ""),
+			})
+
+			req := resource.UpgradeStateRequest{State: &tfsdk.State{Schema: prior, Raw: raw}}
+			resp := resource.UpgradeStateResponse{State: tfsdk.State{Schema: schemaResp.Schema}}
+			r.UpgradeState(ctx)[0].StateUpgrader(ctx, req, &resp)
+			if resp.Diagnostics.HasError() {
+				t.Fatalf("unexpected diagnostics: %v", resp.Diagnostics)
+			}
+
+			var data PasswordModel
+			if diags := resp.State.Get(ctx, &data); diags.HasError() {
+				t.Fatalf("unexpected diagnostics: %v", diags)
+			}
+			if data.ID.ValueString() == id {
+				t.Errorf("id was not replaced: %s", data.ID.ValueString())
+			}
+			if data.LegacyHash.ValueBool() != tt.wantLegacy {
+				t.Errorf("legacy_hash = %t, want %t", data.LegacyHash.ValueBool(), tt.wantLegacy)
+			}
+			if data.Bcrypt.ValueString() != tt.wantBcrypt {
+				t.Errorf("bcrypt = %q, want %q", data.Bcrypt.ValueString(), tt.wantBcrypt)
+			}
+			if data.Apr1.ValueString() != tt.apr1 {
+				t.Errorf("apr1 = %q, want %q", data.Apr1.ValueString(), tt.apr1)
+			}
+			if !data.Yescrypt.IsNull() {
+				t.Errorf("yescrypt = %s, want null", data.Yescrypt)
+			}
+		})
+	}
+}
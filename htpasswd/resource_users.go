@@ -0,0 +1,253 @@
+package htpasswd
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/hashicorp/go-uuid"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringdefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+var _ resource.Resource = &UsersResource{}
+var _ resource.ResourceWithValidateConfig = &UsersResource{}
+var _ resource.ResourceWithConfigure = &UsersResource{}
+
+type UsersResource struct {
+	config *providerConfig
+}
+
+type UsersModel struct {
+	ID              types.String `tfsdk:"id"`
+	Users           types.Map    `tfsdk:"users"`
+	Algorithm       types.String `tfsdk:"algorithm"`
+	Realm           types.String `tfsdk:"realm"`
+	HtpasswdContent types.String `tfsdk:"htpasswd_content"`
+	HtdigestContent types.String `tfsdk:"htdigest_content"`
+}
+
+// fileUsers returns the users of the model as sorted user blocks.
+func (m *UsersModel) fileUsers(ctx context.Context) ([]FileUserModel, diag.Diagnostics) {
+	var passwords map[string]string
+	diags := m.Users.ElementsAs(ctx, &passwords, false)
+
+	users := make([]FileUserModel, 0, len(passwords))
+	for username, password := range passwords {
+		users = append(users, FileUserModel{
+			Username:  types.StringValue(username),
+			Password:  types.StringValue(password),
+			Hash:      types.StringNull(),
+			Algorithm: m.Algorithm,
+		})
+	}
+	slices.SortFunc(users, func(a, b FileUserModel) int {
+		return strings.Compare(a.Username.ValueString(), b.Username.ValueString())
+	})
+	return users, diags
+}
+
+func NewUsersResource() resource.Resource {
+	return &UsersResource{}
+}
+
+func (r *UsersResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_users"
+}
+
+func (r *UsersResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Renders htpasswd content for a map of users",
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Computed:    true,
+				Description: "Resource identifier",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"users": schema.MapAttribute{
+				Required:    true,
+				Sensitive:   true,
+				ElementType: types.StringType,
+				Description: "Map of usernames to passwords",
+			},
+			"algorithm": schema.StringAttribute{
+				Optional:    true,
+				Computed:    true,
+				Default:     stringdefault.StaticString(fileDefaultAlgorithm),
+				Description: "Hash algorithm used for the passwords: " + strings.Join(fileAlgorithms(), ", ") + ". Default: " + fileDefaultAlgorithm,
+			},
+			"realm": schema.StringAttribute{
+				Optional:    true,
+				Description: "Realm of the htdigest_content entries",
+			},
+			"htpasswd_content": schema.StringAttribute{
+				Computed:    true,
+				Description: "The user:hash lines of all users, sorted by username",
+			},
+			"htdigest_content": schema.StringAttribute{
+				Computed:    true,
+				Description: "The user:realm:digest lines of all users, sorted by username. Null unless realm is set.",
+			},
+		},
+	}
+}
+
+func (r *UsersResource) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var data UsersModel
+
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if !data.Algorithm.IsNull() && !data.Algorithm.IsUnknown() && !slices.Contains(fileAlgorithms(), data.Algorithm.ValueString()) {
+		resp.Diagnostics.AddAttributeError(path.Root("algorithm"), "Invalid Algorithm", fmt.Sprintf("algorithm must be one of %s, got %q", strings.Join(fileAlgorithms(), ", "), data.Algorithm.ValueString()))
+	}
+
+	if !data.Realm.IsNull() && !data.Realm.IsUnknown() {
+		if err := validateRealm(data.Realm.ValueString()); err != nil {
+			resp.Diagnostics.AddAttributeError(path.Root("realm"), "Invalid Realm", err.Error())
+		}
+	}
+
+	if data.Users.IsUnknown() {
+		return
+	}
+	for username := range data.Users.Elements() {
+		if err := validateUsername(username); err != nil {
+			resp.Diagnostics.AddAttributeError(path.Root("users").AtMapKey(username), "Invalid Username", err.Error())
+		}
+	}
+}
+
+func (r *UsersResource) Configure(_ context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+	config, ok := req.ProviderData.(*providerConfig)
+	if !ok {
+		resp.Diagnostics.AddError("Unexpected Provider Data", fmt.Sprintf("Expected *providerConfig, got %T", req.ProviderData))
+		return
+	}
+	r.config = config
+}
+
+func (r *UsersResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var data UsersModel
+
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(r.validate(&data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(r.render(ctx, &data, nil)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	id, err := uuid.GenerateUUID()
+	if err != nil {
+		resp.Diagnostics.AddError("ID Error", fmt.Sprintf("Failed to generate resource identifier: %s", err))
+		return
+	}
+	data.ID = types.StringValue(id)
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *UsersResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var data UsersModel
+
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Stored hashes are kept as-is as long as they still verify against the
+	// passwords. Only missing or mismatching hashes are regenerated.
+	resp.Diagnostics.Append(r.render(ctx, &data, parseHtpasswd(data.HtpasswdContent.ValueString()))...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *UsersResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var data, state UsersModel
+
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(r.validate(&data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(r.render(ctx, &data, parseHtpasswd(state.HtpasswdContent.ValueString()))...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *UsersResource) Delete(_ context.Context, _ resource.DeleteRequest, _ *resource.DeleteResponse) {
+}
+
+// validate returns diagnostics for the algorithms of data refused by the
+// provider configuration.
+func (r *UsersResource) validate(data *UsersModel) diag.Diagnostics {
+	var diags diag.Diagnostics
+	if err := r.config.validateAlgorithm(data.Algorithm.ValueString()); err != nil {
+		diags.AddAttributeError(path.Root("algorithm"), "Invalid Algorithm", err.Error())
+	}
+	if data.Realm.ValueString() != "" {
+		if err := r.config.validateAlgorithm(fileFormatHtdigest); err != nil {
+			diags.AddAttributeError(path.Root("realm"), "Invalid Algorithm", fmt.Sprintf("htdigest_content can not be rendered: %s", err))
+		}
+	}
+	return diags
+}
+
+// render sets the htpasswd and htdigest content of data, reusing hashes from
+// existing that still verify.
+func (r *UsersResource) render(ctx context.Context, data *UsersModel, existing []htpasswdEntry) diag.Diagnostics {
+	users, diags := data.fileUsers(ctx)
+	if diags.HasError() {
+		return diags
+	}
+
+	content, diags := renderUsers(users, existing, "")
+	if diags.HasError() {
+		return diags
+	}
+	data.HtpasswdContent = types.StringValue(content)
+
+	data.HtdigestContent = types.StringNull()
+	if realm := data.Realm.ValueString(); realm != "" {
+		content, diags = renderUsers(users, nil, realm)
+		if diags.HasError() {
+			return diags
+		}
+		data.HtdigestContent = types.StringValue(content)
+	}
+	return diags
+}
@@ -0,0 +1,190 @@
+package htpasswd
+
+import (
+	"fmt"
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/terraform"
+)
+
+func TestAccResourceUsers_Basic(t *testing.T) {
+	config := `
+resource "htpasswd_users" "test" {
+	users = {
+		carol = "hunter2"
+		alice = "secret123"
+	}
+}
+`
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: config,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("htpasswd_users.test", "algorithm", "bcrypt"),
+					resource.TestMatchResourceAttr("htpasswd_users.test", "htpasswd_content", regexp.MustCompile(`^alice:\$2a\$10\$[^\n]+\ncarol:\$2a\$10\$[^\n]+\n$`)),
+					testAccCheckUsersContent("htpasswd_users.test", map[string]string{"alice": "secret123", "carol": "hunter2"}),
+				),
+			},
+			{
+				// Hashes with a random salt must not cause a difference.
+				Config:   config,
+				PlanOnly: true,
+			},
+		},
+	})
+}
+
+func TestAccResourceUsers_AddUserKeepsHashes(t *testing.T) {
+	var alice string
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: `
+resource "htpasswd_users" "test" {
+	algorithm = "apr1"
+	users = {
+		alice = "secret123"
+	}
+}
+`,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttrWith("htpasswd_users.test", "htpasswd_content", func(value string) error {
+						alice = value
+						return nil
+					}),
+				),
+			},
+			{
+				Config: `
+resource "htpasswd_users" "test" {
+	algorithm = "apr1"
+	users = {
+		alice = "secret123"
+		bob   = "hunter2"
+	}
+}
+`,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttrWith("htpasswd_users.test", "htpasswd_content", func(value string) error {
+						if !regexp.MustCompile(`^` + regexp.QuoteMeta(alice) + `bob:\$apr1\$[^\n]+\n$`).MatchString(value) {
+							return fmt.Errorf("htpasswd_content %q does not keep the hash of alice %q", value, alice)
+						}
+						return nil
+					}),
+					testAccCheckUsersContent("htpasswd_users.test", map[string]string{"alice": "secret123", "bob": "hunter2"}),
+				),
+			},
+		},
+	})
+}
+
+func TestAccResourceUsers_SaltedHashes(t *testing.T) {
+	config := `
+resource "htpasswd_users" "test" {
+	algorithm = "sha512"
+	users = {
+		alice = "secret123"
+		bob   = "hunter2"
+	}
+}
+`
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: config,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestMatchResourceAttr("htpasswd_users.test", "htpasswd_content", regexp.MustCompile(
+						`^alice:\$6\$[./0-9A-Za-z]{8}\$[^\n]+\nbob:\$6\$[./0-9A-Za-z]{8}\$[^\n]+\n$`)),
+					testAccCheckUsersContent("htpasswd_users.test", map[string]string{"alice": "secret123", "bob": "hunter2"}),
+				),
+			},
+			{
+				Config:   config,
+				PlanOnly: true,
+			},
+			{
+				Config: `
+resource "htpasswd_users" "test" {
+	algorithm = "sha256_crypt"
+	users = {
+		alice = "secret123"
+	}
+}
+`,
+				Check: resource.TestMatchResourceAttr("htpasswd_users.test", "htpasswd_content", regexp.MustCompile(
+					`^alice:\$5\$[./0-9A-Za-z]{8}\$[^\n]+\n$`)),
+			},
+		},
+	})
+}
+
+func TestAccResourceUsers_Htdigest(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: `
+resource "htpasswd_users" "test" {
+	realm = "private"
+	users = {
+		alice = "secret123"
+	}
+}
+`,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("htpasswd_users.test", "htdigest_content", "alice:private:4577980e2c512b92b0c67633674f8f07\n"),
+					testAccCheckUsersContent("htpasswd_users.test", map[string]string{"alice": "secret123"}),
+				),
+			},
+		},
+	})
+}
+
+func TestAccResourceUsers_InvalidUsername(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: `
+resource "htpasswd_users" "test" {
+	users = {
+		"alice:admin" = "secret123"
+	}
+}
+`,
+				ExpectError: regexp.MustCompile(`must not contain colons`),
+			},
+		},
+	})
+}
+
+// testAccCheckUsersContent checks that the htpasswd_content of name contains
+// an entry matching each username and password in passwords.
+func testAccCheckUsersContent(name string, passwords map[string]string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources[name]
+		if !ok {
+			return fmt.Errorf("resource %s not found", name)
+		}
+		entries := parseHtpasswd(rs.Primary.Attributes["htpasswd_content"])
+		if len(entries) != len(passwords) {
+			return fmt.Errorf("expected %d entries, got %d", len(passwords), len(entries))
+		}
+		for _, entry := range entries {
+			hasher, ok := detectHasher(entry.Hash)
+			if !ok || !hasher.Verify(passwords[entry.Username], entry.Hash, HashOptions{}) {
+				return fmt.Errorf("entry of user %q does not verify: %q", entry.Username, entry.Hash)
+			}
+		}
+		return nil
+	}
+}